@@ -126,6 +126,33 @@ func BenchmarkStreamSort(b *testing.B) {
 	}
 }
 
+func BenchmarkStreamToSortedSlice(b *testing.B) {
+	data := make([]int, 1000)
+	for i := range data {
+		data[i] = 1000 - i
+	}
+	s := stream.From(data)
+	for range b.N {
+		_ = s.ToSortedSlice(func(a, b int) int { return a - b })
+	}
+}
+
+func BenchmarkStreamToSlice(b *testing.B) {
+	s := stream.From(benchData).Filter(func(n int) bool { return n >= 0 })
+	b.ReportAllocs()
+	for range b.N {
+		_ = s.ToSlice()
+	}
+}
+
+func BenchmarkStreamToSliceCap(b *testing.B) {
+	s := stream.From(benchData).Filter(func(n int) bool { return n >= 0 })
+	b.ReportAllocs()
+	for range b.N {
+		_ = s.ToSliceCap(len(benchData))
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Chained operations benchmark
 // ---------------------------------------------------------------------------
@@ -0,0 +1,265 @@
+package stream
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// ---------------------------------------------------------------------------
+// Concurrent operations
+// ---------------------------------------------------------------------------
+// These functions fan work out across goroutines. The source Stream itself is
+// always iterated from a single goroutine; only the user-supplied functions
+// run concurrently, so they must be safe for concurrent use.
+
+// ParForEachChunk splits the Stream into chunks of chunkSize and processes
+// them across workers goroutines. It returns the first error reported by fn;
+// once an error occurs no further chunks are dispatched and chunks not yet
+// started are skipped. All goroutines have exited by the time it returns.
+// The final partial chunk is processed as well. A non-positive chunkSize is
+// a no-op, and workers < 1 is treated as 1.
+//
+//	err := stream.ParForEachChunk(rows, 500, 4, func(batch []Row) error {
+//	    return db.BulkInsert(batch)
+//	})
+func ParForEachChunk[T any](s Stream[T], chunkSize, workers int, fn func([]T) error) error {
+	if chunkSize <= 0 {
+		return nil
+	}
+	seq := s.seq
+	chunks := func(yield func([]T) bool) {
+		chunk := make([]T, 0, chunkSize)
+		for v := range seq {
+			chunk = append(chunk, v)
+			if len(chunk) == chunkSize {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, chunkSize)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+	return runWorkers(chunks, workers, fn)
+}
+
+// ParMapErr applies fn to every element across workers goroutines and
+// returns the results in input order, regardless of completion order. On the
+// first error, no further elements are dispatched, elements not yet started
+// are skipped, and the error is returned with a nil slice. All goroutines
+// have exited by the time it returns. workers < 1 is treated as 1.
+//
+//	users, err := stream.ParMapErr(stream.Of(ids...), 8, fetchUser)
+func ParMapErr[T, U any](s Stream[T], workers int, fn func(T) (U, error)) ([]U, error) {
+	var mu sync.Mutex
+	results := []U{}
+	err := runWorkers(Enumerate(s).seq, workers, func(p Pair[int, T]) error {
+		u, err := fn(p.Second)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if n := p.First + 1; n > len(results) {
+			results = append(results, make([]U, n-len(results))...)
+		}
+		results[p.First] = u
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// MapParallel lazily applies fn to each element across a pool of workers
+// goroutines and yields the results in input order. At most workers calls
+// run at once and roughly workers results are buffered ahead of the
+// consumer. When the consumer stops early, in-flight calls are allowed to
+// finish and every goroutine has exited before iteration returns.
+// workers <= 1 falls back to Map.
+//
+//	hashes := stream.MapParallel(stream.Of(files...), 8, hashFile).ToSlice()
+func MapParallel[T, U any](s Stream[T], workers int, fn func(T) U) Stream[U] {
+	if workers <= 1 {
+		return Map(s, fn)
+	}
+	type job struct {
+		v   T
+		res chan U
+	}
+	seq := s.seq
+	return Stream[U]{seq: func(yield func(U) bool) {
+		jobs := make(chan job)
+		order := make(chan chan U, workers)
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range jobs {
+					j.res <- fn(j.v)
+				}
+			}()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(order)
+			defer close(jobs)
+			for v := range seq {
+				j := job{v: v, res: make(chan U, 1)}
+				if !trySend(order, j.res, done) || !trySend(jobs, j, done) {
+					return
+				}
+			}
+		}()
+		defer func() {
+			close(done)
+			wg.Wait()
+		}()
+		for res := range order {
+			if !yield(<-res) {
+				return
+			}
+		}
+	}}
+}
+
+// Buffer reads ahead up to size elements on a separate goroutine while the
+// consumer processes earlier ones, smoothing out bursty or slow sources.
+// The source is iterated on that goroutine, one element at a time and never
+// concurrently with itself. When the consumer stops early the goroutine is
+// signalled and has exited before iteration returns. A non-positive size
+// returns the Stream unchanged.
+//
+//	stream.Collect(fetchPages(client)).Buffer(8).ForEach(process)
+func (s Stream[T]) Buffer(size int) Stream[T] {
+	if size <= 0 {
+		return s
+	}
+	seq := s.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		ch := make(chan T, size)
+		done := make(chan struct{})
+		exited := make(chan struct{})
+		go func() {
+			defer close(exited)
+			defer close(ch)
+			for v := range seq {
+				select {
+				case ch <- v:
+				case <-done:
+					return
+				}
+			}
+		}()
+		defer func() {
+			close(done)
+			<-exited
+		}()
+		for v := range ch {
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// ToChannel starts a goroutine that sends every element into a channel with
+// the given buffer size and closes it once the Stream is exhausted. The
+// channel must be read to completion, otherwise the goroutine blocks
+// forever; use ToChannelCtx when the consumer may stop early.
+//
+//	for v := range s.ToChannel(16) { ... }
+func (s Stream[T]) ToChannel(buffer int) <-chan T {
+	return s.ToChannelCtx(context.Background(), buffer)
+}
+
+// ToChannelCtx starts a goroutine that sends every element into a channel
+// with the given buffer size and returns that channel for consumption
+// elsewhere. The channel is closed when the Stream is exhausted or ctx is
+// done; cancelling ctx is how a consumer that stops reading early releases
+// the goroutine. A negative buffer is treated as 0 (unbuffered).
+//
+//	ctx, cancel := context.WithCancel(ctx)
+//	defer cancel()
+//	for v := range s.ToChannelCtx(ctx, 16) { ... }
+func (s Stream[T]) ToChannelCtx(ctx context.Context, buffer int) <-chan T {
+	ch := make(chan T, max(buffer, 0))
+	seq := s.seq
+	go func() {
+		defer close(ch)
+		for v := range seq {
+			select {
+			case ch <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// trySend sends v on ch unless done is closed first. It reports whether the
+// value was sent.
+func trySend[V any](ch chan<- V, v V, done <-chan struct{}) bool {
+	select {
+	case ch <- v:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// runWorkers feeds every job from jobs to fn across a pool of workers and
+// returns the first error. After an error, the producer stops pulling from
+// jobs and workers skip anything already handed to them.
+func runWorkers[J any](jobs iter.Seq[J], workers int, fn func(J) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	queue := make(chan J)
+	done := make(chan struct{})
+	var (
+		once     sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				select {
+				case <-done:
+					continue
+				default:
+				}
+				if err := fn(job); err != nil {
+					once.Do(func() {
+						firstErr = err
+						close(done)
+					})
+				}
+			}
+		}()
+	}
+
+produce:
+	for job := range jobs {
+		select {
+		case queue <- job:
+		case <-done:
+			break produce
+		}
+	}
+	close(queue)
+	wg.Wait()
+	return firstErr
+}
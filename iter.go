@@ -29,3 +29,82 @@ func Collect2[K, V any](seq iter.Seq2[K, V]) Stream[Pair[K, V]] {
 		}
 	}}
 }
+
+// FlattenSeq lazily concatenates a Stream of iter.Seq values into a flat
+// Stream. Useful when a Map produces standard library iterators rather than
+// slices.
+//
+//	keys := stream.FlattenSeq(stream.Map(stream.Of(configs...), func(c map[string]int) iter.Seq[string] {
+//	    return maps.Keys(c)
+//	}))
+func FlattenSeq[T any](s Stream[iter.Seq[T]]) Stream[T] {
+	seq := s.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		for inner := range seq {
+			for v := range inner {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}}
+}
+
+// CollectDistinct creates a Stream[T] from an iter.Seq[T], dropping duplicate
+// elements while preserving first-occurrence order.
+// Note: Maintains a set of seen elements in memory.
+//
+//	keys := stream.CollectDistinct(maps.Keys(a))
+func CollectDistinct[T comparable](seq iter.Seq[T]) Stream[T] {
+	return CollectDistinctBy(seq, func(v T) T { return v })
+}
+
+// CollectDistinctBy is like CollectDistinct but uses the provided key
+// function to determine equality.
+func CollectDistinctBy[T any, K comparable](seq iter.Seq[T], key func(T) K) Stream[T] {
+	return Stream[T]{seq: func(yield func(T) bool) {
+		seen := make(map[K]struct{})
+		for v := range seq {
+			k := key(v)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// CollectMapped creates a Stream[U] from an iter.Seq[T], applying fn to each
+// element in the same pass. Equivalent to Map(Collect(seq), fn).
+//
+//	labels := stream.CollectMapped(slices.Values(ids), strconv.Itoa)
+func CollectMapped[T, U any](seq iter.Seq[T], fn func(T) U) Stream[U] {
+	return Stream[U]{seq: func(yield func(U) bool) {
+		for v := range seq {
+			if !yield(fn(v)) {
+				return
+			}
+		}
+	}}
+}
+
+// CollectN creates a Stream[T] from at most the first n elements of an
+// iter.Seq[T]. Iteration stops after the nth element, so it is safe to use
+// with unbounded iterators. For n <= 0 the Stream is empty.
+//
+//	first10 := stream.CollectN(readRecords(r), 10)
+func CollectN[T any](seq iter.Seq[T], n int) Stream[T] {
+	return Collect(seq).Take(n)
+}
+
+// ReverseSeq creates a Stream[T] that yields the elements of an iter.Seq[T]
+// in reverse order.
+// Note: This operation consumes all elements into memory.
+//
+//	stream.ReverseSeq(slices.Values([]int{1, 2, 3})) // yields 3, 2, 1
+func ReverseSeq[T any](seq iter.Seq[T]) Stream[T] {
+	return Collect(seq).Reverse()
+}
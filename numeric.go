@@ -1,5 +1,10 @@
 package stream
 
+import (
+	"math"
+	"slices"
+)
+
 // Number is a constraint for numeric types.
 type Number interface {
 	~int | ~int8 | ~int16 | ~int32 | ~int64 |
@@ -56,6 +61,31 @@ func Max[T Number](s Stream[T]) (T, bool) {
 	return max, found
 }
 
+// MinMax returns the minimum and maximum elements of a numeric Stream in a
+// single pass. ok is false if the Stream is empty.
+func MinMax[T Number](s Stream[T]) (min, max T, ok bool) {
+	return MinMaxBy(s, func(a, b T) bool { return a < b })
+}
+
+// MinMaxBy returns the minimum and maximum elements according to less in a
+// single pass. On ties, the first minimum and the first maximum win. ok is
+// false if the Stream is empty.
+func MinMaxBy[T any](s Stream[T], less func(a, b T) bool) (min, max T, ok bool) {
+	for v := range s.seq {
+		if !ok {
+			min, max, ok = v, v, true
+			continue
+		}
+		if less(v, min) {
+			min = v
+		}
+		if less(max, v) {
+			max = v
+		}
+	}
+	return min, max, ok
+}
+
 // SumBy extracts a numeric value from each element and returns the sum.
 func SumBy[T any, N Number](s Stream[T], fn func(T) N) N {
 	var total N
@@ -65,6 +95,23 @@ func SumBy[T any, N Number](s Stream[T], fn func(T) N) N {
 	return total
 }
 
+// Product returns the product of all elements in a numeric Stream, or 1 if
+// the Stream is empty. Integer overflow wraps like native Go multiplication.
+func Product[T Number](s Stream[T]) T {
+	return ProductBy(s, func(v T) T { return v })
+}
+
+// ProductBy extracts a numeric value from each element and returns the
+// product, or 1 if the Stream is empty. Integer overflow wraps like native
+// Go multiplication.
+func ProductBy[T any, N Number](s Stream[T], fn func(T) N) N {
+	total := N(1)
+	for v := range s.seq {
+		total *= fn(v)
+	}
+	return total
+}
+
 // AvgBy extracts a numeric value from each element and returns the average.
 func AvgBy[T any, N Number](s Stream[T], fn func(T) N) float64 {
 	var total float64
@@ -78,3 +125,248 @@ func AvgBy[T any, N Number](s Stream[T], fn func(T) N) float64 {
 	}
 	return total / float64(count)
 }
+
+// ClampCount lazily clamps each element into [lo, hi] and reports how many
+// values were clipped at each bound. The returned counts function reflects
+// the most recent iteration of the Stream, so call it after a terminal
+// operation has run.
+//
+//	clamped, counts := stream.ClampCount(readings, 0, 100)
+//	values := clamped.ToSlice()
+//	low, high := counts()
+func ClampCount[T Number](s Stream[T], lo, hi T) (Stream[T], func() (low, high int)) {
+	seq := s.seq
+	var low, high int
+	clamped := Stream[T]{seq: func(yield func(T) bool) {
+		low, high = 0, 0
+		for v := range seq {
+			if v < lo {
+				v = lo
+				low++
+			} else if v > hi {
+				v = hi
+				high++
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+	return clamped, func() (int, int) { return low, high }
+}
+
+// DistinctAdjacentByTolerance lazily drops elements that are within tol of
+// the previously emitted value, keeping only significant changes. The first
+// element is always emitted. Uses O(1) memory and works on infinite Streams.
+//
+//	// 20.0, 20.1, 19.9, 25.0, 25.2 → 20.0, 25.0
+//	stream.DistinctAdjacentByTolerance(readings, 0.5)
+func DistinctAdjacentByTolerance[T Number](s Stream[T], tol T) Stream[T] {
+	seq := s.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		var last T
+		emitted := false
+		for v := range seq {
+			if emitted {
+				diff := v - last
+				if v < last {
+					diff = last - v
+				}
+				// A negative diff means the subtraction overflowed a signed
+				// type, so the true distance exceeds any tolerance.
+				if diff >= 0 && diff <= tol {
+					continue
+				}
+			}
+			last, emitted = v, true
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// GeometricMean returns the geometric mean of all elements, computed as the
+// exponential of the mean of logarithms to avoid overflow on long inputs.
+// Returns false if the Stream is empty or contains a non-positive value,
+// since the geometric mean is undefined there.
+//
+//	// Average growth factor of 1.10, 1.50, 0.90
+//	g, ok := stream.GeometricMean(stream.Of(1.10, 1.50, 0.90))
+func GeometricMean[T Number](s Stream[T]) (float64, bool) {
+	return GeometricMeanBy(s, func(v T) T { return v })
+}
+
+// GeometricMeanBy extracts a numeric value from each element and returns the
+// geometric mean. See GeometricMean for the handling of empty input and
+// non-positive values.
+func GeometricMeanBy[T any, N Number](s Stream[T], fn func(T) N) (float64, bool) {
+	var logSum float64
+	count := 0
+	for v := range s.seq {
+		x := float64(fn(v))
+		if x <= 0 {
+			return 0, false
+		}
+		logSum += math.Log(x)
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return math.Exp(logSum / float64(count)), true
+}
+
+// Stats holds descriptive statistics computed by Summarize.
+type Stats[T Number] struct {
+	Count int
+	Sum   T
+	Min   T
+	Max   T
+	Mean  float64
+}
+
+// Summarize computes count, sum, min, max, and mean in a single pass.
+// Returns false if the Stream is empty.
+//
+//	stats, ok := stream.Summarize(stream.Of(3, 1, 4, 1, 5))
+//	// {Count: 5, Sum: 14, Min: 1, Max: 5, Mean: 2.8}
+func Summarize[T Number](s Stream[T]) (Stats[T], bool) {
+	return SummarizeBy(s, func(v T) T { return v })
+}
+
+// SummarizeBy extracts a numeric value from each element and computes its
+// Stats in a single pass. Returns false if the Stream is empty.
+func SummarizeBy[T any, N Number](s Stream[T], fn func(T) N) (Stats[N], bool) {
+	var st Stats[N]
+	var total float64
+	for v := range s.seq {
+		x := fn(v)
+		if st.Count == 0 || x < st.Min {
+			st.Min = x
+		}
+		if st.Count == 0 || x > st.Max {
+			st.Max = x
+		}
+		st.Sum += x
+		total += float64(x)
+		st.Count++
+	}
+	if st.Count == 0 {
+		return st, false
+	}
+	st.Mean = total / float64(st.Count)
+	return st, true
+}
+
+// Median returns the middle value of a numeric Stream, averaging the two
+// middle values when the length is even. Returns false if the Stream is empty.
+// Note: This operation consumes all elements into memory.
+func Median[T Number](s Stream[T]) (float64, bool) {
+	buf := slices.Collect(Map(s, func(v T) float64 { return float64(v) }).seq)
+	if len(buf) == 0 {
+		return 0, false
+	}
+	slices.Sort(buf)
+	mid := len(buf) / 2
+	if len(buf)%2 == 0 {
+		return (buf[mid-1] + buf[mid]) / 2, true
+	}
+	return buf[mid], true
+}
+
+// Variance returns the population variance (dividing by n) of a numeric
+// Stream, computed in a single pass. Returns 0 if the Stream is empty.
+// Use SampleVariance for the unbiased estimate from a sample.
+func Variance[T Number](s Stream[T]) float64 {
+	n, m2 := sumSquaredDeviations(s)
+	if n == 0 {
+		return 0
+	}
+	return m2 / float64(n)
+}
+
+// SampleVariance returns the sample variance (dividing by n-1) of a numeric
+// Stream, computed in a single pass. Returns 0 if the Stream has fewer than
+// two elements.
+func SampleVariance[T Number](s Stream[T]) float64 {
+	n, m2 := sumSquaredDeviations(s)
+	if n < 2 {
+		return 0
+	}
+	return m2 / float64(n-1)
+}
+
+// StdDev returns the population standard deviation of a numeric Stream, the
+// square root of Variance. Returns 0 if the Stream is empty.
+func StdDev[T Number](s Stream[T]) float64 {
+	return math.Sqrt(Variance(s))
+}
+
+// sumSquaredDeviations returns the element count and the sum of squared
+// deviations from the mean, using Welford's algorithm for numerical
+// stability.
+func sumSquaredDeviations[T Number](s Stream[T]) (int, float64) {
+	n := 0
+	var mean, m2 float64
+	for v := range s.seq {
+		x := float64(v)
+		n++
+		delta := x - mean
+		mean += delta / float64(n)
+		m2 += delta * (x - mean)
+	}
+	return n, m2
+}
+
+// Resample produces targetLen points by linear interpolation across the
+// series' index range, for up- or down-sampling. The first and last output
+// points always equal the first and last input values (a single output point
+// takes the first value). A single-point series is repeated targetLen times.
+// Returns an empty Stream when the input is empty or targetLen <= 0.
+// Note: This operation consumes all elements into memory.
+//
+//	stream.Resample(stream.Of(0, 10, 20), 5) // 0, 5, 10, 15, 20
+func Resample[T Number](s Stream[T], targetLen int) Stream[float64] {
+	seq := s.seq
+	return Stream[float64]{seq: func(yield func(float64) bool) {
+		if targetLen <= 0 {
+			return
+		}
+		var buf []float64
+		for v := range seq {
+			buf = append(buf, float64(v))
+		}
+		if len(buf) == 0 {
+			return
+		}
+		for i := 0; i < targetLen; i++ {
+			// Dividing last keeps pos exact at both ends and at every point
+			// that lands on an input index.
+			pos := 0.0
+			if targetLen > 1 {
+				pos = float64(i*(len(buf)-1)) / float64(targetLen-1)
+			}
+			lo := int(pos)
+			if lo >= len(buf)-1 {
+				lo = len(buf) - 1
+			}
+			v := buf[lo]
+			if lo+1 < len(buf) {
+				v += (buf[lo+1] - buf[lo]) * (pos - float64(lo))
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// Scale lazily maps each element to float64(v)*factor + offset, e.g. for
+// normalization or unit conversion.
+//
+//	// Celsius → Fahrenheit
+//	fahrenheit := stream.Scale(celsius, 1.8, 32)
+func Scale[T Number](s Stream[T], factor, offset float64) Stream[float64] {
+	return Map(s, func(v T) float64 { return float64(v)*factor + offset })
+}
@@ -6,8 +6,8 @@
 // top-level functions.
 //
 // All operations are lazy by default. Operations that require full data
-// (Sort, Reverse, Shuffle, TakeLast, Chunk, Partition) buffer internally
-// and resume lazy iteration.
+// (Sort, Reverse, Shuffle, Chunk, Partition) buffer internally and resume
+// lazy iteration; TakeLast consumes all elements but keeps only the last n.
 //
 // Usage:
 //
@@ -26,15 +26,21 @@
 package stream
 
 import (
+	"bufio"
+	"context"
+	"io"
 	"iter"
 	"math/rand"
+	"slices"
 	"sort"
+	"sync"
 )
 
 // Stream is a lazy evaluation wrapper around iter.Seq[T] that supports
 // method chaining. All intermediate operations are deferred until a terminal
 // operation is called. Operations that require full data (Sort, Reverse,
-// Shuffle, TakeLast) buffer internally then resume lazy iteration.
+// Shuffle) buffer internally then resume lazy iteration; TakeLast consumes
+// all elements but keeps only the last n.
 //
 // Stream is reusable: calling terminal operations multiple times produces
 // the same result, as the underlying iter.Seq is re-executed each time.
@@ -64,6 +70,79 @@ func From[T any](items []T) Stream[T] {
 	return Of(copied...)
 }
 
+// FromSeq creates a new Stream by collecting all elements of an iter.Seq
+// immediately. Unlike Collect, which wraps the iterator lazily, the source
+// is consumed once and the Stream replays the collected elements, so it is
+// safe to use with single-use iterators.
+// Warning: Do not use with infinite iterators.
+func FromSeq[T any](seq iter.Seq[T]) Stream[T] {
+	return Of(slices.Collect(seq)...)
+}
+
+// FromChannel creates a lazy Stream that receives from ch until it is
+// closed. Receiving consumes the values, so the Stream is single-use: a
+// second iteration continues from wherever the channel is. If iteration
+// stops early (e.g. Take), the remaining values are left unread in ch.
+//
+//	stream.FromChannel(results).Filter(isValid).ForEach(store)
+func FromChannel[T any](ch <-chan T) Stream[T] {
+	return Stream[T]{seq: func(yield func(T) bool) {
+		for v := range ch {
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// FromReader creates a lazy Stream of the lines read from r, without their
+// line endings. Lines are read only as they are consumed, so Take stops
+// reading early. Reading consumes r, so the Stream is single-use. A read
+// error, or a line longer than bufio.MaxScanTokenSize, ends the Stream; the
+// returned err function reports it (nil on a clean EOF or early stop), so
+// call it after a terminal operation has run.
+//
+//	lines, err := stream.FromReader(file)
+//	errors := lines.Filter(isError).ToSlice()
+//	if err() != nil { ... }
+func FromReader(r io.Reader) (Stream[string], func() error) {
+	return FromReaderFunc(r, func(sc *bufio.Scanner) (string, bool) {
+		if !sc.Scan() {
+			return "", false
+		}
+		return sc.Text(), true
+	})
+}
+
+// FromReaderFunc creates a lazy Stream whose elements are produced by scan
+// from a line-splitting bufio.Scanner over r. scan is called once per
+// element; it advances the scanner as many times as it needs (e.g. to read
+// multi-line records) and returns false to end the Stream. Like FromReader,
+// the Stream is single-use and the returned err function reports the
+// scanner's error once iteration has ended.
+//
+//	records, err := stream.FromReaderFunc(r, func(sc *bufio.Scanner) (Record, bool) {
+//	    if !sc.Scan() {
+//	        return Record{}, false
+//	    }
+//	    header := sc.Text()
+//	    if !sc.Scan() {
+//	        return Record{}, false
+//	    }
+//	    return Record{Header: header, Body: sc.Text()}, true
+//	})
+func FromReaderFunc[T any](r io.Reader, scan func(*bufio.Scanner) (T, bool)) (Stream[T], func() error) {
+	sc := bufio.NewScanner(r)
+	return Stream[T]{seq: func(yield func(T) bool) {
+		for {
+			v, ok := scan(sc)
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	}}, sc.Err
+}
+
 // Generate creates a Stream of n elements using a generator function.
 func Generate[T any](n int, gen func(index int) T) Stream[T] {
 	return Stream[T]{seq: func(yield func(T) bool) {
@@ -75,6 +154,17 @@ func Generate[T any](n int, gen func(index int) T) Stream[T] {
 	}}
 }
 
+// Generate2 creates a Stream of n index/value Pairs using a generator
+// function, equivalent to Generate followed by pairing each value with its
+// index.
+//
+//	squares := stream.Generate2(5, func(i int) int { return i * i })
+func Generate2[T any](n int, gen func(index int) T) Stream[Pair[int, T]] {
+	return Generate(n, func(i int) Pair[int, T] {
+		return Pair[int, T]{First: i, Second: gen(i)}
+	})
+}
+
 // Range creates a Stream of integers from start (inclusive) to end (exclusive).
 func Range(start, end int) Stream[int] {
 	return Stream[int]{seq: func(yield func(int) bool) {
@@ -220,24 +310,54 @@ func (s Stream[T]) Take(n int) Stream[T] {
 	}}
 }
 
-// TakeLast buffers all elements and returns only the last n elements.
-// Note: This operation consumes all elements into memory.
+// TakeLast returns only the last n elements, yielded once the source is
+// exhausted. It keeps a ring buffer of at most n elements.
+// Note: This operation consumes the entire Stream before yielding.
 func (s Stream[T]) TakeLast(n int) Stream[T] {
 	if n <= 0 {
 		return Stream[T]{seq: func(yield func(T) bool) {}}
 	}
 	seq := s.seq
 	return Stream[T]{seq: func(yield func(T) bool) {
-		var buf []T
+		ring := make([]T, 0, min(n, 64))
+		pos := 0
 		for v := range seq {
-			buf = append(buf, v)
+			if len(ring) < n {
+				ring = append(ring, v)
+				continue
+			}
+			ring[pos] = v
+			pos = (pos + 1) % n
 		}
-		start := len(buf) - n
-		if start < 0 {
-			start = 0
+		for i := range ring {
+			if !yield(ring[(pos+i)%len(ring)]) {
+				return
+			}
 		}
-		for _, v := range buf[start:] {
-			if !yield(v) {
+	}}
+}
+
+// SkipLast returns a Stream without its last n elements. It buffers only n
+// elements in a ring buffer, yielding each element once n newer ones have
+// arrived, so it streams and works on infinite sequences. A non-positive n
+// returns the Stream unchanged; n larger than the length yields nothing.
+func (s Stream[T]) SkipLast(n int) Stream[T] {
+	if n <= 0 {
+		return s
+	}
+	seq := s.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		ring := make([]T, 0, n)
+		pos := 0
+		for v := range seq {
+			if len(ring) < n {
+				ring = append(ring, v)
+				continue
+			}
+			oldest := ring[pos]
+			ring[pos] = v
+			pos = (pos + 1) % n
+			if !yield(oldest) {
 				return
 			}
 		}
@@ -279,6 +399,23 @@ func (s Stream[T]) TakeWhile(predicate func(T) bool) Stream[T] {
 	}}
 }
 
+// WithContext stops the Stream once ctx is done. ctx.Err() is checked before
+// each element is passed downstream, so terminal operations return whatever
+// was produced up to that point. Use it to bound infinite Streams by a
+// deadline or request cancellation.
+//
+//	ids := stream.Naturals().WithContext(ctx).Filter(isPrime).ToSlice()
+func (s Stream[T]) WithContext(ctx context.Context) Stream[T] {
+	seq := s.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		for v := range seq {
+			if ctx.Err() != nil || !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
 // DropWhile skips elements from the start while the predicate is true,
 // then yields the rest.
 func (s Stream[T]) DropWhile(predicate func(T) bool) Stream[T] {
@@ -299,6 +436,34 @@ func (s Stream[T]) DropWhile(predicate func(T) bool) Stream[T] {
 	}}
 }
 
+// TakeEvery returns a Stream that yields every step-th element, starting
+// with the first: indices 0, step, 2*step, ... step = 1 yields everything;
+// step <= 0 yields an empty Stream.
+//
+//	stream.Range(0, 10).TakeEvery(2) // 0, 2, 4, 6, 8
+func (s Stream[T]) TakeEvery(step int) Stream[T] {
+	if step <= 0 {
+		return Stream[T]{seq: func(yield func(T) bool) {}}
+	}
+	seq := s.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		i := 0
+		for v := range seq {
+			if i%step == 0 {
+				if !yield(v) {
+					return
+				}
+			}
+			i++
+		}
+	}}
+}
+
+// SampleEveryN is an alias of TakeEvery.
+func (s Stream[T]) SampleEveryN(n int) Stream[T] {
+	return s.TakeEvery(n)
+}
+
 // Distinct returns a Stream with duplicate elements removed.
 // Uses the provided key function to determine equality.
 // Note: Maintains a set of seen keys in memory.
@@ -318,16 +483,42 @@ func (s Stream[T]) Distinct(key func(T) string) Stream[T] {
 	}}
 }
 
+// RunningDistinctCount yields, for each element, the number of distinct keys
+// seen so far, e.g. a "unique visitors over time" curve.
+// Note: Maintains a set of seen keys in memory.
+func (s Stream[T]) RunningDistinctCount(key func(T) string) Stream[int] {
+	seq := s.seq
+	return Stream[int]{seq: func(yield func(int) bool) {
+		seen := make(map[string]struct{})
+		for v := range seq {
+			seen[key(v)] = struct{}{}
+			if !yield(len(seen)) {
+				return
+			}
+		}
+	}}
+}
+
 // Shuffle buffers all elements, randomizes their order, and yields them.
 // Note: This operation consumes all elements into memory.
 func (s Stream[T]) Shuffle() Stream[T] {
+	return s.shuffle(rand.Shuffle)
+}
+
+// ShuffleWithRand is like Shuffle but draws randomness from r, making the
+// output deterministic for a given seed.
+func (s Stream[T]) ShuffleWithRand(r *rand.Rand) Stream[T] {
+	return s.shuffle(r.Shuffle)
+}
+
+func (s Stream[T]) shuffle(shuffle func(n int, swap func(i, j int))) Stream[T] {
 	seq := s.seq
 	return Stream[T]{seq: func(yield func(T) bool) {
 		var buf []T
 		for v := range seq {
 			buf = append(buf, v)
 		}
-		rand.Shuffle(len(buf), func(i, j int) {
+		shuffle(len(buf), func(i, j int) {
 			buf[i], buf[j] = buf[j], buf[i]
 		})
 		for _, v := range buf {
@@ -338,6 +529,105 @@ func (s Stream[T]) Shuffle() Stream[T] {
 	}}
 }
 
+// ShuffleBuffer performs an approximate streaming shuffle using a buffer of
+// size elements: once the buffer is full, each new element replaces a random
+// buffered one, which is emitted. The remaining buffer is shuffled at the
+// end. Memory is O(size) and it works on infinite Streams.
+// Note: An element is emitted at most size positions earlier than its
+// original place; its delay is unbounded but averages about size positions,
+// so this is a local, not global, shuffle. When size is at least the Stream
+// length it is equivalent to Shuffle. A non-positive size returns the
+// Stream unchanged.
+func (s Stream[T]) ShuffleBuffer(size int) Stream[T] {
+	return s.shuffleBuffer(size, rand.Intn)
+}
+
+// ShuffleBufferWithRand is like ShuffleBuffer but draws randomness from r,
+// making the output deterministic for a given seed.
+func (s Stream[T]) ShuffleBufferWithRand(size int, r *rand.Rand) Stream[T] {
+	return s.shuffleBuffer(size, r.Intn)
+}
+
+func (s Stream[T]) shuffleBuffer(size int, intn func(int) int) Stream[T] {
+	if size <= 0 {
+		return s
+	}
+	seq := s.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		buf := make([]T, 0, size)
+		for v := range seq {
+			if len(buf) < size {
+				buf = append(buf, v)
+				continue
+			}
+			j := intn(size)
+			out := buf[j]
+			buf[j] = v
+			if !yield(out) {
+				return
+			}
+		}
+		for i := len(buf) - 1; i > 0; i-- {
+			j := intn(i + 1)
+			buf[i], buf[j] = buf[j], buf[i]
+		}
+		for _, v := range buf {
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// Sample selects a uniformly random subset of n elements using reservoir
+// sampling (Algorithm R), holding at most n elements in memory regardless
+// of input size. Streams with fewer than n elements yield all of them. The
+// sample is emitted once the source is exhausted, so the source must be
+// finite. A non-positive n yields an empty Stream.
+func (s Stream[T]) Sample(n int) Stream[T] {
+	return s.sample(n, rand.Intn)
+}
+
+// SampleWithRand is like Sample but draws randomness from r, making the
+// output deterministic for a given seed.
+func (s Stream[T]) SampleWithRand(n int, r *rand.Rand) Stream[T] {
+	return s.sample(n, r.Intn)
+}
+
+func (s Stream[T]) sample(n int, intn func(int) int) Stream[T] {
+	if n <= 0 {
+		return Stream[T]{seq: func(yield func(T) bool) {}}
+	}
+	seq := s.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		reservoir := make([]T, 0, n)
+		i := 0
+		for v := range seq {
+			if len(reservoir) < n {
+				reservoir = append(reservoir, v)
+			} else if j := intn(i + 1); j < n {
+				reservoir[j] = v
+			}
+			i++
+		}
+		for _, v := range reservoir {
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// Scan yields the initial value followed by each successive accumulation,
+// e.g. prefix sums or a running maximum. An empty Stream yields only the
+// initial value. For accumulating into a different type, use the top-level
+// Scan function.
+//
+//	stream.Of(1, 2, 3).Scan(0, func(acc, n int) int { return acc + n }) // 0, 1, 3, 6
+func (s Stream[T]) Scan(initial T, fn func(acc, item T) T) Stream[T] {
+	return Scan(s, initial, fn)
+}
+
 // Peek executes a side-effect function for each element without modifying the Stream.
 // Useful for debugging or logging within a lazy chain.
 func (s Stream[T]) Peek(fn func(T)) Stream[T] {
@@ -352,6 +642,59 @@ func (s Stream[T]) Peek(fn func(T)) Stream[T] {
 	}}
 }
 
+// PeekIndexed is like Peek but also passes each element's index, e.g. for
+// progress logging. The index restarts at 0 on every iteration.
+//
+//	s.PeekIndexed(func(i int, _ Row) {
+//	    if i%5000 == 0 { log.Printf("processed %d rows", i) }
+//	})
+func (s Stream[T]) PeekIndexed(fn func(int, T)) Stream[T] {
+	seq := s.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		i := 0
+		for v := range seq {
+			fn(i, v)
+			if !yield(v) {
+				return
+			}
+			i++
+		}
+	}}
+}
+
+// Tap is an alias of Peek: it executes fn for each element as it flows
+// through, without modifying the Stream.
+func (s Stream[T]) Tap(fn func(T)) Stream[T] {
+	return s.Peek(fn)
+}
+
+// TapOnce executes fn the first time an element flows through, e.g. to log
+// that a pipeline started. fn runs at most once per iteration and never for
+// an empty Stream.
+func (s Stream[T]) TapOnce(fn func()) Stream[T] {
+	return s.OnFirst(func(T) { fn() })
+}
+
+// OnFirst invokes fn with the first element as it flows through, then passes
+// every element on unchanged. fn runs at most once per iteration and never
+// for an empty Stream. Useful for capturing a header row or initializing
+// state lazily.
+func (s Stream[T]) OnFirst(fn func(T)) Stream[T] {
+	seq := s.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		first := true
+		for v := range seq {
+			if first {
+				fn(v)
+				first = false
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
 // Chain concatenates multiple Streams, yielding all elements from each in order.
 //
 //	combined := s1.Chain(s2, s3)
@@ -373,6 +716,181 @@ func (s Stream[T]) Chain(others ...Stream[T]) Stream[T] {
 	}}
 }
 
+// Cycle returns an infinite Stream that replays the elements of s endlessly,
+// re-iterating the source on each pass; bound it with Take or TakeWhile.
+// If a pass yields no elements the Stream ends, so cycling an empty Stream
+// yields nothing rather than looping forever.
+//
+//	stream.Of(1, 2, 3).Cycle().Take(7) // 1, 2, 3, 1, 2, 3, 1
+func (s Stream[T]) Cycle() Stream[T] {
+	seq := s.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		for {
+			empty := true
+			for v := range seq {
+				empty = false
+				if !yield(v) {
+					return
+				}
+			}
+			if empty {
+				return
+			}
+		}
+	}}
+}
+
+// Tee splits the Stream into n Streams that each see the full sequence,
+// while the source is iterated only once, like Python's itertools.tee. This
+// makes a single-use source (channel, reader) consumable n times. Elements
+// pulled by one branch are queued for the others until they catch up, so
+// memory grows with the distance between the fastest and slowest branch; a
+// branch that is never iterated buffers everything. The branches may be
+// consumed from different goroutines.
+// Each returned Stream is single-use: a branch that stops early is detached
+// and yields nothing more. Returns nil for a non-positive n.
+//
+//	branches := stream.FromChannel(events).Tee(2)
+//	go audit(branches[0])
+//	process(branches[1])
+func (s Stream[T]) Tee(n int) []Stream[T] {
+	if n <= 0 {
+		return nil
+	}
+	t := &tee[T]{src: s.seq, queues: make([][]T, n), active: make([]bool, n), remaining: n}
+	branches := make([]Stream[T], n)
+	for i := range branches {
+		t.active[i] = true
+		branches[i] = Stream[T]{seq: func(yield func(T) bool) {
+			defer t.detach(i)
+			for {
+				v, ok := t.next(i)
+				if !ok || !yield(v) {
+					return
+				}
+			}
+		}}
+	}
+	return branches
+}
+
+// Memoize returns a Stream that caches elements as they are first pulled
+// from s and replays them from the cache on later iterations, so the source
+// runs at most once even across several terminal operations. The cache
+// fills lazily: an iteration that stops early leaves the source suspended
+// where it was, and the next iteration replays the cached prefix before
+// resuming it. Safe for concurrent iteration.
+// Note: The cache holds every element pulled, and a source that is never
+// fully consumed stays suspended until the program exits.
+//
+//	rows := stream.Collect(queryRows(db)).Memoize()
+//	n := rows.Count()
+//	first := rows.Take(10).ToSlice() // served from the cache
+func (s Stream[T]) Memoize() Stream[T] {
+	m := &memo[T]{src: s.seq}
+	return Stream[T]{seq: func(yield func(T) bool) {
+		for i := 0; ; i++ {
+			v, ok := m.at(i)
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// memo is the cache shared by iterations of a memoized Stream.
+type memo[T any] struct {
+	mu        sync.Mutex
+	src       iter.Seq[T]
+	pull      func() (T, bool)
+	cache     []T
+	exhausted bool
+}
+
+// at returns the i-th element, pulling from the source if it is not cached.
+// Iterations advance one element at a time, so i never exceeds len(cache).
+func (m *memo[T]) at(i int) (T, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if i < len(m.cache) {
+		return m.cache[i], true
+	}
+	var zero T
+	if m.exhausted {
+		return zero, false
+	}
+	if m.pull == nil {
+		m.pull, _ = iter.Pull(m.src)
+	}
+	v, ok := m.pull()
+	if !ok {
+		m.exhausted = true
+		return zero, false
+	}
+	m.cache = append(m.cache, v)
+	return v, true
+}
+
+// tee is the state shared by the branches returned from Stream.Tee.
+type tee[T any] struct {
+	mu        sync.Mutex
+	src       iter.Seq[T]
+	pull      func() (T, bool)
+	stop      func()
+	exhausted bool
+	queues    [][]T
+	active    []bool
+	remaining int
+}
+
+// next returns the next element for branch i, pulling from the source and
+// queueing it for the other active branches when i's queue is empty.
+func (t *tee[T]) next(i int) (T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var zero T
+	if !t.active[i] {
+		return zero, false
+	}
+	if q := t.queues[i]; len(q) > 0 {
+		t.queues[i] = q[1:]
+		return q[0], true
+	}
+	if t.exhausted {
+		return zero, false
+	}
+	if t.pull == nil {
+		t.pull, t.stop = iter.Pull(t.src)
+	}
+	v, ok := t.pull()
+	if !ok {
+		t.exhausted = true
+		return zero, false
+	}
+	for j := range t.queues {
+		if j != i && t.active[j] {
+			t.queues[j] = append(t.queues[j], v)
+		}
+	}
+	return v, true
+}
+
+// detach stops buffering for branch i and releases the source once every
+// branch has finished.
+func (t *tee[T]) detach(i int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.active[i] {
+		return
+	}
+	t.active[i] = false
+	t.queues[i] = nil
+	t.remaining--
+	if t.remaining == 0 && t.stop != nil {
+		t.stop()
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Terminal operations (consume the Stream)
 // ---------------------------------------------------------------------------
@@ -389,12 +907,64 @@ func (s Stream[T]) ToSlice() []T {
 	return result
 }
 
+// ToSliceCap collects all elements into a slice preallocated with the given
+// capacity, avoiding repeated growth when the size is known or estimated.
+// The hint only affects allocation: longer Streams still grow the slice and
+// shorter ones leave spare capacity. A negative capacity is treated as 0.
+//
+//	ids := stream.Map(stream.Of(users...), User.ID).ToSliceCap(len(users))
+func (s Stream[T]) ToSliceCap(capacity int) []T {
+	result := make([]T, 0, max(capacity, 0))
+	for v := range s.seq {
+		result = append(result, v)
+	}
+	return result
+}
+
+// ToSortedSlice collects all elements into a slice and sorts it in place.
+// Equivalent to Sort(cmp).ToSlice() without the intermediate lazy stage.
+func (s Stream[T]) ToSortedSlice(cmp func(a, b T) int) []T {
+	result := s.ToSlice()
+	slices.SortFunc(result, cmp)
+	return result
+}
+
+// ToSliceDistinct collects all elements into a slice and, in the same pass,
+// counts how many distinct keys they produce. Duplicates are kept in the
+// slice; only the count is deduplicated.
+//
+//	rows, unique := s.ToSliceDistinct(func(r Row) string { return r.ID })
+func (s Stream[T]) ToSliceDistinct(key func(T) string) ([]T, int) {
+	result := []T{}
+	seen := make(map[string]struct{})
+	for v := range s.seq {
+		result = append(result, v)
+		seen[key(v)] = struct{}{}
+	}
+	return result, len(seen)
+}
+
 // Seq returns the underlying iter.Seq[T].
 // Use this for interop with standard library functions like slices.Collect.
 func (s Stream[T]) Seq() iter.Seq[T] {
 	return s.seq
 }
 
+// Seq2 returns an iter.Seq2[int, T] yielding each element with its index,
+// enabling `for i, v := range s.Seq2()`.
+func (s Stream[T]) Seq2() iter.Seq2[int, T] {
+	seq := s.seq
+	return func(yield func(int, T) bool) {
+		i := 0
+		for v := range seq {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
+
 // ForEach executes a function for each element.
 func (s Stream[T]) ForEach(fn func(T)) {
 	for v := range s.seq {
@@ -402,6 +972,19 @@ func (s Stream[T]) ForEach(fn func(T)) {
 	}
 }
 
+// CollectFunc passes each element to collector. It behaves like ForEach but
+// signals that the callback accumulates results (e.g. into a builder).
+func (s Stream[T]) CollectFunc(collector func(T)) {
+	s.ForEach(collector)
+}
+
+// Drain consumes the Stream, discarding its elements. Use it to run a lazy
+// chain purely for its side effects (e.g. Peek).
+func (s Stream[T]) Drain() {
+	for range s.seq {
+	}
+}
+
 // ForEachIndexed executes a function for each element with its index.
 func (s Stream[T]) ForEachIndexed(fn func(int, T)) {
 	i := 0
@@ -411,6 +994,98 @@ func (s Stream[T]) ForEachIndexed(fn func(int, T)) {
 	}
 }
 
+// ForEachUntil executes a function for each element until it returns false.
+// Short-circuits: stops iteration on the first false.
+func (s Stream[T]) ForEachUntil(fn func(T) bool) {
+	for v := range s.seq {
+		if !fn(v) {
+			return
+		}
+	}
+}
+
+// ForEachIndexedWhile executes a function for each element with its index
+// until it returns false. Short-circuits: stops iteration on the first false.
+func (s Stream[T]) ForEachIndexedWhile(fn func(int, T) bool) {
+	i := 0
+	for v := range s.seq {
+		if !fn(i, v) {
+			return
+		}
+		i++
+	}
+}
+
+// ForEachCollectErr executes fn for every element and collects all non-nil
+// errors instead of stopping at the first one. Returns nil when every call
+// succeeds.
+//
+//	errs := stream.Of(records...).ForEachCollectErr(save)
+//	if len(errs) > 0 { ... }
+func (s Stream[T]) ForEachCollectErr(fn func(T) error) []error {
+	var errs []error
+	for v := range s.seq {
+		if err := fn(v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// ForEachChunk calls fn with each batch of size elements as soon as it is
+// formed, including the final partial batch, without collecting the chunks
+// first. A non-positive size is a no-op. The batch slice is reused between
+// calls, so fn must not retain it. See ForEachBatchCtx for cancellation and
+// error handling.
+//
+//	users.ForEachChunk(500, func(batch []User) { db.InsertUsers(batch) })
+func (s Stream[T]) ForEachChunk(size int, fn func([]T)) {
+	_ = s.ForEachBatchCtx(context.Background(), size, func(_ context.Context, batch []T) error {
+		fn(batch)
+		return nil
+	})
+}
+
+// ForEachBatchCtx groups elements into batches of size and calls fn for each
+// batch, flushing the final partial batch. It stops at the first error
+// returned by fn, or with ctx.Err() once ctx is done; the context is checked
+// for every element pulled and before each call, so a slowly filling batch
+// does not delay cancellation. A non-positive size is a no-op. The batch
+// slice is reused between calls, so fn must not retain it.
+//
+//	err := s.ForEachBatchCtx(ctx, 500, func(ctx context.Context, rows []Row) error {
+//		return db.InsertRows(ctx, rows)
+//	})
+func (s Stream[T]) ForEachBatchCtx(ctx context.Context, size int, fn func(context.Context, []T) error) error {
+	if size <= 0 {
+		return nil
+	}
+	flush := func(batch []T) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fn(ctx, batch)
+	}
+	batch := make([]T, 0, size)
+	for v := range s.seq {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch = append(batch, v)
+		if len(batch) < size {
+			continue
+		}
+		if err := flush(batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+	}
+	if len(batch) > 0 {
+		return flush(batch)
+	}
+	return nil
+}
+
 // Reduce folds all elements into a single value of the same type.
 // For reducing to a different type, use the top-level Reduce function.
 func (s Stream[T]) Reduce(initial T, fn func(acc, item T) T) T {
@@ -443,6 +1118,23 @@ func (s Stream[T]) Last() (T, bool) {
 	return last, found
 }
 
+// ElementAt returns the element at 0-based index n and true, or zero value
+// and false if the Stream is shorter or n is negative.
+// Short-circuits: stops iteration once the element is reached.
+func (s Stream[T]) ElementAt(n int) (T, bool) {
+	if n >= 0 {
+		i := 0
+		for v := range s.seq {
+			if i == n {
+				return v, true
+			}
+			i++
+		}
+	}
+	var zero T
+	return zero, false
+}
+
 // Find returns the first element matching the predicate.
 // Short-circuits: stops iteration as soon as a match is found.
 func (s Stream[T]) Find(predicate func(T) bool) (T, bool) {
@@ -455,6 +1147,20 @@ func (s Stream[T]) Find(predicate func(T) bool) (T, bool) {
 	return zero, false
 }
 
+// FindIndex returns the 0-based index of the first element matching the
+// predicate, or -1 and false if none matches.
+// Short-circuits: stops iteration as soon as a match is found.
+func (s Stream[T]) FindIndex(predicate func(T) bool) (int, bool) {
+	i := 0
+	for v := range s.seq {
+		if predicate(v) {
+			return i, true
+		}
+		i++
+	}
+	return -1, false
+}
+
 // Any returns true if any element satisfies the predicate.
 // Short-circuits on first match.
 func (s Stream[T]) Any(predicate func(T) bool) bool {
@@ -532,6 +1238,41 @@ func (s Stream[T]) Partition(predicate func(T) bool) (matched Stream[T], unmatch
 	return From(yes), From(no)
 }
 
+// Span collects all elements and splits them at the first element that
+// fails the predicate: prefix holds the leading run that satisfies it (as
+// TakeWhile would) and rest holds everything from that element on (as
+// DropWhile would).
+// Note: This operation consumes all elements into memory.
+//
+//	prefix, rest := stream.Of(1, 2, 3, 4, 1).Span(func(n int) bool { return n < 4 })
+//	// prefix: 1, 2, 3; rest: 4, 1
+func (s Stream[T]) Span(predicate func(T) bool) (prefix Stream[T], rest Stream[T]) {
+	var head, tail []T
+	for v := range s.seq {
+		if tail == nil && predicate(v) {
+			head = append(head, v)
+		} else {
+			tail = append(tail, v)
+		}
+	}
+	return From(head), From(tail)
+}
+
+// SplitByPredicate consumes the Stream and returns two slices: elements that
+// satisfy the predicate and those that don't. Useful for reporting what
+// passed validation alongside what was rejected.
+func (s Stream[T]) SplitByPredicate(predicate func(T) bool) (kept []T, rejected []T) {
+	kept, rejected = []T{}, []T{}
+	for v := range s.seq {
+		if predicate(v) {
+			kept = append(kept, v)
+		} else {
+			rejected = append(rejected, v)
+		}
+	}
+	return kept, rejected
+}
+
 // Chunk collects all elements and splits them into chunks of the specified size.
 // Note: This operation consumes all elements into memory.
 func (s Stream[T]) Chunk(size int) []Stream[T] {
@@ -555,6 +1296,20 @@ func (s Stream[T]) Chunk(size int) []Stream[T] {
 	return chunks
 }
 
+// ChunkPadded is like Chunk but fills the final partial chunk with pad, so
+// every chunk has exactly size elements. Returns nil for a non-positive size.
+// Note: This operation consumes all elements into memory.
+func (s Stream[T]) ChunkPadded(size int, pad T) []Stream[T] {
+	if size <= 0 {
+		return nil
+	}
+	buf := s.ToSlice()
+	for len(buf)%size != 0 {
+		buf = append(buf, pad)
+	}
+	return Of(buf...).Chunk(size)
+}
+
 // MinBy returns the minimum element according to the comparison function.
 // Warning: Consumes the entire Stream.
 func (s Stream[T]) MinBy(less func(a, b T) bool) (T, bool) {
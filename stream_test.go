@@ -1,9 +1,18 @@
 package stream_test
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"iter"
+	"math"
+	"math/rand"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/nd-forge/stream"
 )
@@ -73,6 +82,125 @@ func TestGenerate(t *testing.T) {
 	}
 }
 
+func TestFromChannel(t *testing.T) {
+	ch := make(chan int, 5)
+	for i := range 5 {
+		ch <- i
+	}
+	close(ch)
+	result := stream.FromChannel(ch).Filter(func(n int) bool { return n%2 == 0 }).ToSlice()
+	if !slices.Equal(result, []int{0, 2, 4}) {
+		t.Errorf("FromChannel: expected [0 2 4], got %v", result)
+	}
+}
+
+func TestFromChannel_EarlyBreak(t *testing.T) {
+	ch := make(chan int, 5)
+	for i := range 5 {
+		ch <- i
+	}
+	close(ch)
+	result := stream.FromChannel(ch).Take(2).ToSlice()
+	if !slices.Equal(result, []int{0, 1}) {
+		t.Errorf("FromChannel early break: expected [0 1], got %v", result)
+	}
+	if len(ch) != 3 {
+		t.Errorf("FromChannel early break: expected 3 unread values, got %d", len(ch))
+	}
+}
+
+func TestFromReader(t *testing.T) {
+	lines, err := stream.FromReader(strings.NewReader("alpha\nbeta\r\n\ngamma"))
+	result := lines.ToSlice()
+	if !slices.Equal(result, []string{"alpha", "beta", "", "gamma"}) {
+		t.Errorf("FromReader: expected [alpha beta  gamma], got %q", result)
+	}
+	if err() != nil {
+		t.Errorf("FromReader: unexpected error %v", err())
+	}
+}
+
+func TestFromReader_Lazy(t *testing.T) {
+	r := strings.NewReader(strings.Repeat("line\n", 100_000))
+	lines, _ := stream.FromReader(r)
+	result := lines.Take(2).ToSlice()
+	if len(result) != 2 || result[0] != "line" {
+		t.Errorf("FromReader lazy: unexpected %v", result)
+	}
+	if r.Len() == 0 {
+		t.Error("FromReader lazy: expected the reader not to be fully consumed")
+	}
+}
+
+func TestFromReader_TooLong(t *testing.T) {
+	input := "first\n" + strings.Repeat("x", bufio.MaxScanTokenSize+1) + "\nlast\n"
+	lines, err := stream.FromReader(strings.NewReader(input))
+	result := lines.ToSlice()
+	if !slices.Equal(result, []string{"first"}) {
+		t.Errorf("FromReader too long: expected [first], got %d lines", len(result))
+	}
+	if err() != bufio.ErrTooLong {
+		t.Errorf("FromReader too long: expected bufio.ErrTooLong, got %v", err())
+	}
+}
+
+func TestFromReaderFunc(t *testing.T) {
+	input := "a\n1\nb\n2\nc"
+	records, err := stream.FromReaderFunc(strings.NewReader(input), func(sc *bufio.Scanner) (stream.Pair[string, string], bool) {
+		if !sc.Scan() {
+			return stream.Pair[string, string]{}, false
+		}
+		key := sc.Text()
+		if !sc.Scan() {
+			return stream.Pair[string, string]{}, false
+		}
+		return stream.Pair[string, string]{First: key, Second: sc.Text()}, true
+	})
+	pairs := records.ToSlice()
+	expected := []stream.Pair[string, string]{{First: "a", Second: "1"}, {First: "b", Second: "2"}}
+	if !slices.Equal(pairs, expected) || err() != nil {
+		t.Errorf("FromReaderFunc: expected %v, got %v (err %v)", expected, pairs, err())
+	}
+}
+
+func TestGenerate2(t *testing.T) {
+	result := stream.Generate2(4, func(i int) int { return i * i }).ToSlice()
+	expected := []stream.Pair[int, int]{{First: 0, Second: 0}, {First: 1, Second: 1}, {First: 2, Second: 4}, {First: 3, Second: 9}}
+	if !slices.Equal(result, expected) {
+		t.Errorf("Generate2: expected %v, got %v", expected, result)
+	}
+	if n := stream.Generate2(-1, func(i int) int { return i }).Count(); n != 0 {
+		t.Errorf("Generate2(-1): expected 0 elements, got %d", n)
+	}
+}
+
+func TestFromSeq(t *testing.T) {
+	pulls := 0
+	source := func(yield func(int) bool) {
+		for _, v := range []int{5, 1, 4, 2, 3} {
+			pulls++
+			if !yield(v) {
+				return
+			}
+		}
+	}
+	s := stream.FromSeq(source)
+	result := s.Filter(func(n int) bool { return n > 1 }).
+		Sort(func(a, b int) int { return a - b }).
+		ToSlice()
+	if !slices.Equal(result, []int{2, 3, 4, 5}) {
+		t.Errorf("FromSeq: expected [2 3 4 5], got %v", result)
+	}
+	// Re-running the Stream replays collected values without re-pulling.
+	if s.Count() != 5 || pulls != 5 {
+		t.Errorf("FromSeq: expected source pulled once (5), got %d", pulls)
+	}
+
+	if values := stream.FromSeq(slices.Values([]string{"a", "b"})).ToSlice(); !slices.Equal(values, []string{"a", "b"}) {
+		t.Errorf("FromSeq slices.Values: unexpected %v", values)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Generator tests (infinite sequences)
 // ---------------------------------------------------------------------------
@@ -196,6 +324,40 @@ func TestTakeLast(t *testing.T) {
 	}
 }
 
+func TestTakeLast_Ring(t *testing.T) {
+	for _, n := range []int{1, 3, 7} {
+		got := stream.Range(0, 1000).TakeLast(n).ToSlice()
+		if !slices.Equal(got, stream.Range(1000-n, 1000).ToSlice()) {
+			t.Errorf("TakeLast(%d): unexpected %v", n, got)
+		}
+	}
+}
+
+func TestSkipLast(t *testing.T) {
+	tests := []struct {
+		n        int
+		expected []int
+	}{
+		{2, []int{1, 2, 3}},
+		{0, []int{1, 2, 3, 4, 5}},
+		{-1, []int{1, 2, 3, 4, 5}},
+		{5, []int{}},
+		{9, []int{}},
+	}
+	for _, tt := range tests {
+		if got := stream.Of(1, 2, 3, 4, 5).SkipLast(tt.n).ToSlice(); !slices.Equal(got, tt.expected) {
+			t.Errorf("SkipLast(%d): expected %v, got %v", tt.n, tt.expected, got)
+		}
+	}
+}
+
+func TestSkipLast_Infinite(t *testing.T) {
+	result := stream.Naturals().SkipLast(3).Take(4).ToSlice()
+	if !slices.Equal(result, []int{0, 1, 2, 3}) {
+		t.Errorf("SkipLast infinite: expected [0 1 2 3], got %v", result)
+	}
+}
+
 func TestTakeZero(t *testing.T) {
 	result := stream.Of(1, 2, 3).Take(0).ToSlice()
 	if len(result) != 0 {
@@ -235,6 +397,18 @@ func TestShuffle(t *testing.T) {
 	}
 }
 
+func TestShuffleWithRand(t *testing.T) {
+	a := stream.Range(0, 20).ShuffleWithRand(rand.New(rand.NewSource(42))).ToSlice()
+	b := stream.Range(0, 20).ShuffleWithRand(rand.New(rand.NewSource(42))).ToSlice()
+	if !slices.Equal(a, b) {
+		t.Errorf("ShuffleWithRand: expected same order for same seed, got %v and %v", a, b)
+	}
+	sorted := slices.Sorted(slices.Values(a))
+	if !slices.Equal(sorted, stream.Range(0, 20).ToSlice()) || slices.Equal(a, sorted) {
+		t.Errorf("ShuffleWithRand: expected a shuffled permutation of 0..19, got %v", a)
+	}
+}
+
 func TestChain(t *testing.T) {
 	s1 := stream.Of(1, 2, 3)
 	s2 := stream.Of(4, 5, 6)
@@ -244,6 +418,158 @@ func TestChain(t *testing.T) {
 	}
 }
 
+func TestTee(t *testing.T) {
+	ch := make(chan int, 5)
+	for i := range 5 {
+		ch <- i
+	}
+	close(ch)
+	branches := stream.FromChannel(ch).Tee(2)
+	a := branches[0].Take(2).ToSlice()
+	b := branches[1].ToSlice()
+	if !slices.Equal(a, []int{0, 1}) || !slices.Equal(b, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("Tee: expected [0 1] and [0 1 2 3 4], got %v and %v", a, b)
+	}
+	if again := branches[0].ToSlice(); len(again) != 0 {
+		t.Errorf("Tee: expected detached branch to yield nothing, got %v", again)
+	}
+	if stream.Of(1).Tee(0) != nil {
+		t.Error("Tee(0): expected nil")
+	}
+}
+
+func TestTee_SinglePass(t *testing.T) {
+	pulls := 0
+	branches := stream.Range(0, 4).Peek(func(int) { pulls++ }).Tee(3)
+	for i, b := range branches {
+		if got := b.ToSlice(); !slices.Equal(got, []int{0, 1, 2, 3}) {
+			t.Errorf("Tee branch %d: expected [0 1 2 3], got %v", i, got)
+		}
+	}
+	if pulls != 4 {
+		t.Errorf("Tee: expected the source to be iterated once, got %d pulls", pulls)
+	}
+}
+
+func TestTee_ReleasesSource(t *testing.T) {
+	released := false
+	src := stream.Collect(func(yield func(int) bool) {
+		defer func() { released = true }()
+		for i := 0; yield(i); i++ {
+		}
+	})
+	branches := src.Tee(2)
+	branches[0].Take(3).Drain()
+	if released {
+		t.Fatal("Tee: source released while a branch is still active")
+	}
+	branches[1].Take(1).Drain()
+	if !released {
+		t.Error("Tee: expected source to be released once every branch stopped")
+	}
+}
+
+func TestTee_Concurrent(t *testing.T) {
+	branches := stream.Range(0, 1000).Tee(4)
+	sums := make([]int, len(branches))
+	var wg sync.WaitGroup
+	for i, b := range branches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sums[i] = stream.Sum(b)
+		}()
+	}
+	wg.Wait()
+	for i, sum := range sums {
+		if sum != 499500 {
+			t.Errorf("Tee concurrent: branch %d expected sum 499500, got %d", i, sum)
+		}
+	}
+}
+
+func TestMemoize(t *testing.T) {
+	runs := 0
+	src := stream.Collect(func(yield func(int) bool) {
+		runs++
+		for i := range 5 {
+			if !yield(i) {
+				return
+			}
+		}
+	})
+	m := src.Memoize()
+	if got := m.ToSlice(); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("Memoize: expected [0 1 2 3 4], got %v", got)
+	}
+	if n := m.Count(); n != 5 {
+		t.Errorf("Memoize: expected count 5, got %d", n)
+	}
+	if runs != 1 {
+		t.Errorf("Memoize: expected the source to run once, got %d", runs)
+	}
+}
+
+func TestMemoize_PartialThenFull(t *testing.T) {
+	pulls := 0
+	ch := make(chan int, 6)
+	for i := range 6 {
+		ch <- i
+	}
+	close(ch)
+	m := stream.FromChannel(ch).Peek(func(int) { pulls++ }).Memoize()
+	if got := m.Take(2).ToSlice(); !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("Memoize partial: expected [0 1], got %v", got)
+	}
+	if got := m.ToSlice(); !slices.Equal(got, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("Memoize after partial: expected [0 1 2 3 4 5], got %v", got)
+	}
+	if pulls != 6 {
+		t.Errorf("Memoize: expected each element pulled once, got %d pulls", pulls)
+	}
+}
+
+func TestMemoize_Concurrent(t *testing.T) {
+	m := stream.Range(0, 500).Memoize()
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sum := stream.Sum(m); sum != 124750 {
+				t.Errorf("Memoize concurrent: expected sum 124750, got %d", sum)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCycle(t *testing.T) {
+	result := stream.Of(1, 2, 3).Cycle().Take(7).ToSlice()
+	if !slices.Equal(result, []int{1, 2, 3, 1, 2, 3, 1}) {
+		t.Errorf("Cycle: expected [1 2 3 1 2 3 1], got %v", result)
+	}
+	if result := stream.Of[int]().Cycle().Take(3).ToSlice(); len(result) != 0 {
+		t.Errorf("Cycle empty: expected empty, got %v", result)
+	}
+}
+
+func TestCycle_ReiteratesSource(t *testing.T) {
+	passes := 0
+	src := stream.Collect(func(yield func(int) bool) {
+		passes++
+		for _, v := range []int{1, 2} {
+			if !yield(v) {
+				return
+			}
+		}
+	})
+	src.Cycle().Take(5).Drain()
+	if passes != 3 {
+		t.Errorf("Cycle: expected 3 passes over the source, got %d", passes)
+	}
+}
+
 func TestSortThenTake(t *testing.T) {
 	// Sort buffers all, but Take after Sort is still lazy
 	result := stream.Of(5, 3, 1, 4, 2).
@@ -273,6 +599,24 @@ func TestPeek(t *testing.T) {
 	}
 }
 
+func TestSpan(t *testing.T) {
+	calls := 0
+	prefix, rest := stream.Of(1, 2, 3, 4, 1).Span(func(n int) bool { calls++; return n < 4 })
+	if got := prefix.ToSlice(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Span prefix: expected [1 2 3], got %v", got)
+	}
+	if got := rest.ToSlice(); !slices.Equal(got, []int{4, 1}) {
+		t.Errorf("Span rest: expected [4 1], got %v", got)
+	}
+	if calls != 4 {
+		t.Errorf("Span: expected predicate to stop after the first failure, got %d calls", calls)
+	}
+	all, none := stream.Of(1, 2).Span(func(int) bool { return true })
+	if all.Count() != 2 || none.Count() != 0 {
+		t.Errorf("Span all match: unexpected split %d/%d", all.Count(), none.Count())
+	}
+}
+
 func TestPartition(t *testing.T) {
 	inStock, outOfStock := stream.Of(
 		Product{Name: "Laptop", Price: 1200, InStock: true},
@@ -285,6 +629,35 @@ func TestPartition(t *testing.T) {
 	}
 }
 
+func TestSplitByPredicate(t *testing.T) {
+	kept, rejected := stream.Of("42", "abc", "7", "", "x1").
+		SplitByPredicate(func(s string) bool {
+			if s == "" {
+				return false
+			}
+			for _, r := range s {
+				if r < '0' || r > '9' {
+					return false
+				}
+			}
+			return true
+		})
+
+	if len(kept) != 2 || kept[0] != "42" || kept[1] != "7" {
+		t.Errorf("SplitByPredicate: unexpected kept %v", kept)
+	}
+	if len(rejected) != 3 || rejected[0] != "abc" || rejected[1] != "" || rejected[2] != "x1" {
+		t.Errorf("SplitByPredicate: unexpected rejected %q", rejected)
+	}
+}
+
+func TestSplitByPredicate_Empty(t *testing.T) {
+	kept, rejected := stream.Of[int]().SplitByPredicate(func(n int) bool { return n > 0 })
+	if kept == nil || rejected == nil || len(kept) != 0 || len(rejected) != 0 {
+		t.Errorf("SplitByPredicate empty: expected two empty slices, got %v %v", kept, rejected)
+	}
+}
+
 func TestChunk(t *testing.T) {
 	chunks := stream.Of(1, 2, 3, 4, 5).Chunk(2)
 	if len(chunks) != 3 {
@@ -295,6 +668,295 @@ func TestChunk(t *testing.T) {
 	}
 }
 
+func TestChunkPadded(t *testing.T) {
+	chunks := stream.Of(1, 2, 3, 4, 5).ChunkPadded(2, 0)
+	expected := [][]int{{1, 2}, {3, 4}, {5, 0}}
+	if len(chunks) != len(expected) {
+		t.Fatalf("ChunkPadded: expected %d chunks, got %d", len(expected), len(chunks))
+	}
+	for i, c := range chunks {
+		if got := c.ToSlice(); !slices.Equal(got, expected[i]) {
+			t.Errorf("ChunkPadded: expected %v at %d, got %v", expected[i], i, got)
+		}
+	}
+	if full := stream.Of(1, 2, 3, 4).ChunkPadded(2, 0); len(full) != 2 || full[1].Count() != 2 {
+		t.Errorf("ChunkPadded exact: expected 2 chunks of 2, got %d", len(full))
+	}
+	if result := stream.Of(1, 2, 3).ChunkPadded(0, 0); result != nil {
+		t.Errorf("ChunkPadded(0): expected nil, got %v", result)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	result := stream.Naturals().WithContext(ctx).Peek(func(n int) {
+		if n == 5 {
+			cancel()
+		}
+	}).ToSlice()
+	if !slices.Equal(result, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("WithContext: expected [0 1 2 3 4 5], got %v", result)
+	}
+}
+
+func TestWithContext_InfiniteFilter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	result := stream.Naturals().WithContext(ctx).Filter(func(n int) bool {
+		if n == 1000 {
+			cancel()
+		}
+		return false
+	}).ToSlice()
+	if len(result) != 0 {
+		t.Errorf("WithContext infinite filter: expected empty, got %v", result)
+	}
+}
+
+func TestWithContext_NotCancelled(t *testing.T) {
+	result := stream.Of(1, 2, 3).WithContext(context.Background()).ToSlice()
+	if !slices.Equal(result, []int{1, 2, 3}) {
+		t.Errorf("WithContext: expected [1 2 3], got %v", result)
+	}
+}
+
+func TestOnFirst(t *testing.T) {
+	var header string
+	calls := 0
+	rows := stream.Of("id,name", "1,alice", "2,bob", "3,carol").
+		OnFirst(func(s string) {
+			header = s
+			calls++
+		}).
+		Skip(1).
+		ToSlice()
+
+	if calls != 1 || header != "id,name" {
+		t.Errorf("OnFirst: expected one call with header, got %d calls, header %q", calls, header)
+	}
+	if len(rows) != 3 || rows[0] != "1,alice" {
+		t.Errorf("OnFirst: iteration should continue normally, got %v", rows)
+	}
+}
+
+func TestOnFirst_Empty(t *testing.T) {
+	calls := 0
+	stream.Of[int]().OnFirst(func(int) { calls++ }).ToSlice()
+	if calls != 0 {
+		t.Errorf("OnFirst empty: expected no calls, got %d", calls)
+	}
+}
+
+func TestOnFirst_EarlyBreak(t *testing.T) {
+	calls := 0
+	v, ok := stream.Naturals().OnFirst(func(int) { calls++ }).First()
+	if !ok || v != 0 || calls != 1 {
+		t.Errorf("OnFirst early break: expected 0 with 1 call, got %d with %d calls", v, calls)
+	}
+}
+
+func TestShuffleBuffer(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	result := stream.Range(0, 20).ShuffleBufferWithRand(20, r).ToSlice()
+	sorted := slices.Clone(result)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, stream.Range(0, 20).ToSlice()) {
+		t.Errorf("ShuffleBuffer: expected a permutation of 0..19, got %v", result)
+	}
+	if slices.Equal(result, sorted) {
+		t.Errorf("ShuffleBuffer: expected shuffled order, got %v", result)
+	}
+}
+
+func TestShuffleBuffer_SmallWindow(t *testing.T) {
+	result := stream.Range(0, 100).ShuffleBuffer(5).ToSlice()
+	sorted := slices.Clone(result)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, stream.Range(0, 100).ToSlice()) {
+		t.Errorf("ShuffleBuffer(5): expected a permutation of 0..99, got %v", result)
+	}
+}
+
+func TestShuffleBuffer_Infinite(t *testing.T) {
+	result := stream.Naturals().ShuffleBuffer(4).Take(10).ToSlice()
+	if len(result) != 10 {
+		t.Errorf("ShuffleBuffer infinite: expected 10 elements, got %v", result)
+	}
+}
+
+func TestShuffleBuffer_EarlyBreakInTail(t *testing.T) {
+	v, ok := stream.Of(1, 2, 3).ShuffleBuffer(10).First()
+	if !ok || v < 1 || v > 3 {
+		t.Errorf("ShuffleBuffer early break: unexpected %d", v)
+	}
+	result := stream.Of(1, 2, 3).ShuffleBuffer(0).ToSlice()
+	if !slices.Equal(result, []int{1, 2, 3}) {
+		t.Errorf("ShuffleBuffer(0): expected unchanged stream, got %v", result)
+	}
+}
+
+func TestSample(t *testing.T) {
+	result := stream.Range(0, 1000).SampleWithRand(10, rand.New(rand.NewSource(42))).ToSlice()
+	if len(result) != 10 {
+		t.Fatalf("Sample: expected 10 elements, got %v", result)
+	}
+	seen := map[int]bool{}
+	for _, v := range result {
+		if v < 0 || v >= 1000 || seen[v] {
+			t.Errorf("Sample: expected distinct elements of 0..999, got %v", result)
+		}
+		seen[v] = true
+	}
+	again := stream.Range(0, 1000).SampleWithRand(10, rand.New(rand.NewSource(42))).ToSlice()
+	if !slices.Equal(result, again) {
+		t.Errorf("SampleWithRand: expected deterministic output, got %v and %v", result, again)
+	}
+}
+
+func TestSample_Uniform(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	counts := make([]int, 5)
+	for range 5000 {
+		for _, v := range stream.Range(0, 5).SampleWithRand(1, r).ToSlice() {
+			counts[v]++
+		}
+	}
+	for i, c := range counts {
+		if c < 850 || c > 1150 {
+			t.Errorf("Sample uniform: element %d chosen %d times out of 5000", i, c)
+		}
+	}
+}
+
+func TestSample_Small(t *testing.T) {
+	if result := stream.Of(1, 2, 3).Sample(5).ToSlice(); !slices.Equal(result, []int{1, 2, 3}) {
+		t.Errorf("Sample(5) of 3: expected [1 2 3], got %v", result)
+	}
+	if result := stream.Of(1, 2, 3).Sample(0).ToSlice(); len(result) != 0 {
+		t.Errorf("Sample(0): expected empty, got %v", result)
+	}
+	if v, ok := stream.Range(0, 100).Sample(3).First(); !ok || v < 0 || v >= 100 {
+		t.Errorf("Sample early break: unexpected %d, %v", v, ok)
+	}
+}
+
+func TestTakeEvery(t *testing.T) {
+	result := stream.Range(0, 10).TakeEvery(2).ToSlice()
+	if !slices.Equal(result, []int{0, 2, 4, 6, 8}) {
+		t.Errorf("TakeEvery(2): expected [0 2 4 6 8], got %v", result)
+	}
+	if result := stream.Range(0, 3).TakeEvery(-1).ToSlice(); len(result) != 0 {
+		t.Errorf("TakeEvery(-1): expected empty, got %v", result)
+	}
+}
+
+func TestSampleEveryN(t *testing.T) {
+	result := stream.Range(0, 10).SampleEveryN(4).ToSlice()
+	if !slices.Equal(result, []int{0, 4, 8}) {
+		t.Errorf("SampleEveryN(4): expected [0 4 8], got %v", result)
+	}
+	if result := stream.Range(0, 3).SampleEveryN(1).ToSlice(); !slices.Equal(result, []int{0, 1, 2}) {
+		t.Errorf("SampleEveryN(1): expected [0 1 2], got %v", result)
+	}
+	if result := stream.Range(0, 3).SampleEveryN(0).ToSlice(); len(result) != 0 {
+		t.Errorf("SampleEveryN(0): expected empty, got %v", result)
+	}
+}
+
+func TestSampleEveryN_EarlyBreak(t *testing.T) {
+	result := stream.Naturals().SampleEveryN(3).Take(2).ToSlice()
+	if !slices.Equal(result, []int{0, 3}) {
+		t.Errorf("SampleEveryN early break: expected [0 3], got %v", result)
+	}
+}
+
+func TestRunningDistinctCount(t *testing.T) {
+	result := stream.Of("u1", "u2", "u1", "u3", "u2", "u4").
+		RunningDistinctCount(func(s string) string { return s }).
+		ToSlice()
+	if !slices.Equal(result, []int{1, 2, 2, 3, 3, 4}) {
+		t.Errorf("RunningDistinctCount: expected [1 2 2 3 3 4], got %v", result)
+	}
+}
+
+func TestRunningDistinctCount_EarlyBreak(t *testing.T) {
+	result := stream.Naturals().
+		RunningDistinctCount(func(n int) string { return fmt.Sprint(n % 2) }).
+		Take(3).
+		ToSlice()
+	if !slices.Equal(result, []int{1, 2, 2}) {
+		t.Errorf("RunningDistinctCount early break: expected [1 2 2], got %v", result)
+	}
+}
+
+func TestScan(t *testing.T) {
+	add := func(acc, n int) int { return acc + n }
+	if result := stream.Of(1, 2, 3).Scan(0, add).ToSlice(); !slices.Equal(result, []int{0, 1, 3, 6}) {
+		t.Errorf("Scan: expected [0 1 3 6], got %v", result)
+	}
+	if result := stream.Of[int]().Scan(5, add).ToSlice(); !slices.Equal(result, []int{5}) {
+		t.Errorf("Scan empty: expected [5], got %v", result)
+	}
+
+	runningMax := stream.Of(3, 1, 4, 1, 5).Scan(0, func(acc, n int) int { return max(acc, n) }).ToSlice()
+	if !slices.Equal(runningMax, []int{0, 3, 3, 4, 4, 5}) {
+		t.Errorf("Scan running max: expected [0 3 3 4 4 5], got %v", runningMax)
+	}
+}
+
+func TestScan_EarlyBreak(t *testing.T) {
+	add := func(acc, n int) int { return acc + n }
+	if result := stream.Naturals().Scan(0, add).Take(4).ToSlice(); !slices.Equal(result, []int{0, 0, 1, 3}) {
+		t.Errorf("Scan early break: expected [0 0 1 3], got %v", result)
+	}
+	if v, ok := stream.Naturals().Scan(7, add).First(); !ok || v != 7 {
+		t.Errorf("Scan early break on seed: expected 7, got %d", v)
+	}
+}
+
+func TestPeekIndexed(t *testing.T) {
+	var seen []string
+	s := stream.Of("a", "b", "c").PeekIndexed(func(i int, v string) {
+		seen = append(seen, fmt.Sprintf("%d:%s", i, v))
+	})
+	if got := s.ToSlice(); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("PeekIndexed: expected elements unchanged, got %v", got)
+	}
+	if !slices.Equal(seen, []string{"0:a", "1:b", "2:c"}) {
+		t.Errorf("PeekIndexed: unexpected %v", seen)
+	}
+	seen = nil
+	s.Take(1).Drain()
+	if !slices.Equal(seen, []string{"0:a"}) {
+		t.Errorf("PeekIndexed: expected index to restart and stay lazy, got %v", seen)
+	}
+}
+
+func TestTap(t *testing.T) {
+	var tapped []int
+	result := stream.Of(1, 2, 3).Tap(func(n int) { tapped = append(tapped, n) }).ToSlice()
+	if !slices.Equal(tapped, []int{1, 2, 3}) || !slices.Equal(result, []int{1, 2, 3}) {
+		t.Errorf("Tap: unexpected tapped %v result %v", tapped, result)
+	}
+}
+
+func TestTapOnce(t *testing.T) {
+	for _, n := range []int{1, 10, 1000} {
+		calls := 0
+		count := stream.Range(0, n).TapOnce(func() { calls++ }).Count()
+		if calls != 1 || count != n {
+			t.Errorf("TapOnce(len %d): expected 1 call and %d elements, got %d calls and %d", n, n, calls, count)
+		}
+	}
+
+	calls := 0
+	stream.Of[int]().TapOnce(func() { calls++ }).Drain()
+	if calls != 0 {
+		t.Errorf("TapOnce empty: expected no calls, got %d", calls)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Terminal operation tests
 // ---------------------------------------------------------------------------
@@ -318,6 +980,33 @@ func TestFirstAndLast(t *testing.T) {
 	}
 }
 
+func TestFindIndex(t *testing.T) {
+	if i, ok := stream.Of(5, 8, 13, 21).FindIndex(func(n int) bool { return n > 10 }); !ok || i != 2 {
+		t.Errorf("FindIndex: expected 2, got %d, %v", i, ok)
+	}
+	if i, ok := stream.Of(1, 2).FindIndex(func(n int) bool { return n > 10 }); ok || i != -1 {
+		t.Errorf("FindIndex no match: expected -1, false, got %d, %v", i, ok)
+	}
+	if i, ok := stream.Naturals().FindIndex(func(n int) bool { return n*n > 50 }); !ok || i != 8 {
+		t.Errorf("FindIndex infinite: expected 8, got %d, %v", i, ok)
+	}
+}
+
+func TestElementAt(t *testing.T) {
+	if v, ok := stream.Of("a", "b", "c").ElementAt(1); !ok || v != "b" {
+		t.Errorf("ElementAt(1): expected b, got %q, %v", v, ok)
+	}
+	if v, ok := stream.Naturals().ElementAt(1000); !ok || v != 1000 {
+		t.Errorf("ElementAt infinite: expected 1000, got %d, %v", v, ok)
+	}
+	if _, ok := stream.Of(1, 2).ElementAt(2); ok {
+		t.Error("ElementAt out of range: expected false")
+	}
+	if _, ok := stream.Of(1, 2).ElementAt(-1); ok {
+		t.Error("ElementAt(-1): expected false")
+	}
+}
+
 func TestFind(t *testing.T) {
 	p, ok := stream.Of(
 		Product{Name: "Laptop", Price: 1200},
@@ -417,209 +1106,2211 @@ func TestReduce(t *testing.T) {
 	}
 }
 
-func TestSeq(t *testing.T) {
-	s := stream.Of(1, 2, 3)
-	var result []int
-	for v := range s.Seq() {
-		result = append(result, v)
+func TestToSliceCap(t *testing.T) {
+	result := stream.Range(0, 100).Filter(func(n int) bool { return n%2 == 0 }).ToSliceCap(50)
+	if len(result) != 50 || cap(result) != 50 || result[49] != 98 {
+		t.Errorf("ToSliceCap: expected 50 elements in a 50-capacity slice, got len %d cap %d", len(result), cap(result))
 	}
-	if len(result) != 3 {
-		t.Errorf("Seq: unexpected %v", result)
+	if grown := stream.Range(0, 10).ToSliceCap(2); !slices.Equal(grown, stream.Range(0, 10).ToSlice()) {
+		t.Errorf("ToSliceCap undersized: unexpected %v", grown)
+	}
+	if empty := stream.Of[int]().ToSliceCap(-1); empty == nil || len(empty) != 0 {
+		t.Errorf("ToSliceCap(-1) empty: expected non-nil empty slice, got %v", empty)
 	}
 }
 
-// ---------------------------------------------------------------------------
-// Transform function tests (type-changing)
-// ---------------------------------------------------------------------------
+func TestToSliceDistinct(t *testing.T) {
+	result, distinct := stream.Of("a", "b", "a", "c", "b", "a").ToSliceDistinct(func(s string) string { return s })
+	if len(result) != 6 || distinct != 3 {
+		t.Errorf("ToSliceDistinct: expected 6 elements and 3 distinct, got %d and %d", len(result), distinct)
+	}
+	if !slices.Equal(result, []string{"a", "b", "a", "c", "b", "a"}) {
+		t.Errorf("ToSliceDistinct: expected input order preserved, got %v", result)
+	}
 
-func TestMap(t *testing.T) {
-	result := stream.Map(
+	empty, n := stream.Of[int]().ToSliceDistinct(func(v int) string { return fmt.Sprint(v) })
+	if empty == nil || len(empty) != 0 || n != 0 {
+		t.Errorf("ToSliceDistinct empty: expected [] and 0, got %v and %d", empty, n)
+	}
+}
+
+func TestToSortedSlice(t *testing.T) {
+	result := stream.Of(3, 1, 4, 1, 5, 9).ToSortedSlice(func(a, b int) int { return a - b })
+	expected := []int{1, 1, 3, 4, 5, 9}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("ToSortedSlice: expected %d at %d, got %d", expected[i], i, v)
+		}
+	}
+}
+
+func TestToSortedSlice_Empty(t *testing.T) {
+	result := stream.Of[int]().ToSortedSlice(func(a, b int) int { return a - b })
+	if result == nil || len(result) != 0 {
+		t.Errorf("ToSortedSlice empty: expected non-nil empty slice, got %v", result)
+	}
+}
+
+func TestSeq(t *testing.T) {
+	s := stream.Of(1, 2, 3)
+	var result []int
+	for v := range s.Seq() {
+		result = append(result, v)
+	}
+	if len(result) != 3 {
+		t.Errorf("Seq: unexpected %v", result)
+	}
+}
+
+func TestForEachCollectErr(t *testing.T) {
+	calls := 0
+	errs := stream.Of(1, 2, 3, 4, 5).ForEachCollectErr(func(n int) error {
+		calls++
+		if n%2 == 0 {
+			return fmt.Errorf("item %d failed", n)
+		}
+		return nil
+	})
+	if calls != 5 {
+		t.Errorf("ForEachCollectErr: expected 5 calls, got %d", calls)
+	}
+	if len(errs) != 2 || errs[0].Error() != "item 2 failed" || errs[1].Error() != "item 4 failed" {
+		t.Errorf("ForEachCollectErr: unexpected errors %v", errs)
+	}
+}
+
+func TestForEachCollectErr_NoErrors(t *testing.T) {
+	errs := stream.Of(1, 2, 3).ForEachCollectErr(func(int) error { return nil })
+	if errs != nil {
+		t.Errorf("ForEachCollectErr: expected nil slice, got %v", errs)
+	}
+}
+
+func TestSeq2(t *testing.T) {
+	var indices, values []int
+	for i, v := range stream.Range(0, 10).Filter(func(n int) bool { return n%3 == 0 }).Seq2() {
+		indices = append(indices, i)
+		values = append(values, v)
+	}
+	if !slices.Equal(indices, []int{0, 1, 2, 3}) || !slices.Equal(values, []int{0, 3, 6, 9}) {
+		t.Errorf("Seq2: unexpected indices %v values %v", indices, values)
+	}
+}
+
+func TestSeq2_EarlyBreak(t *testing.T) {
+	count := 0
+	for i := range stream.Naturals().Seq2() {
+		if i == 2 {
+			break
+		}
+		count++
+	}
+	if count != 2 {
+		t.Errorf("Seq2 early break: expected 2 iterations, got %d", count)
+	}
+}
+
+func TestCollectFunc(t *testing.T) {
+	var sb strings.Builder
+	stream.Of("a", "b", "c").CollectFunc(func(s string) { sb.WriteString(s) })
+	if sb.String() != "abc" {
+		t.Errorf("CollectFunc: expected 'abc', got %q", sb.String())
+	}
+}
+
+func TestDrain(t *testing.T) {
+	var logged []int
+	stream.Of(1, 2, 3, 4).
+		Filter(func(n int) bool { return n%2 == 0 }).
+		Peek(func(n int) { logged = append(logged, n) }).
+		Drain()
+	if !slices.Equal(logged, []int{2, 4}) {
+		t.Errorf("Drain: expected Peek to see [2 4], got %v", logged)
+	}
+}
+
+func TestForEachUntil(t *testing.T) {
+	evaluated := 0
+	var seen []int
+	stream.Naturals().
+		Peek(func(int) { evaluated++ }).
+		ForEachUntil(func(n int) bool {
+			seen = append(seen, n)
+			return n < 3
+		})
+	if !slices.Equal(seen, []int{0, 1, 2, 3}) {
+		t.Errorf("ForEachUntil: unexpected %v", seen)
+	}
+	if evaluated != 4 {
+		t.Errorf("ForEachUntil: expected 4 evaluations, got %d", evaluated)
+	}
+}
+
+func TestForEachIndexedWhile(t *testing.T) {
+	evaluated := 0
+	var seen []string
+	stream.Of("a", "b", "c", "d", "e").
+		Peek(func(string) { evaluated++ }).
+		ForEachIndexedWhile(func(i int, s string) bool {
+			seen = append(seen, fmt.Sprintf("%d:%s", i, s))
+			return i < 2
+		})
+	if !slices.Equal(seen, []string{"0:a", "1:b", "2:c"}) {
+		t.Errorf("ForEachIndexedWhile: unexpected %v", seen)
+	}
+	if evaluated != 3 {
+		t.Errorf("ForEachIndexedWhile: expected 3 evaluations, got %d", evaluated)
+	}
+}
+
+func TestForEachChunk(t *testing.T) {
+	var sizes []int
+	stream.Range(0, 7).ForEachChunk(3, func(batch []int) { sizes = append(sizes, len(batch)) })
+	if !slices.Equal(sizes, []int{3, 3, 1}) {
+		t.Errorf("ForEachChunk: expected batch sizes [3 3 1], got %v", sizes)
+	}
+	called := false
+	stream.Range(0, 7).ForEachChunk(0, func([]int) { called = true })
+	if called {
+		t.Error("ForEachChunk(0): expected no-op")
+	}
+}
+
+func TestForEachBatchCtx(t *testing.T) {
+	var batches [][]int
+	err := stream.Range(1, 8).ForEachBatchCtx(context.Background(), 3, func(_ context.Context, b []int) error {
+		batches = append(batches, slices.Clone(b))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachBatchCtx: unexpected error %v", err)
+	}
+	expected := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}
+	if len(batches) != len(expected) {
+		t.Fatalf("ForEachBatchCtx: expected %v, got %v", expected, batches)
+	}
+	for i := range batches {
+		if !slices.Equal(batches[i], expected[i]) {
+			t.Errorf("ForEachBatchCtx: expected %v at %d, got %v", expected[i], i, batches[i])
+		}
+	}
+}
+
+func TestForEachBatchCtx_Error(t *testing.T) {
+	errBoom := fmt.Errorf("boom")
+	calls := 0
+	err := stream.Naturals().ForEachBatchCtx(context.Background(), 2, func(_ context.Context, b []int) error {
+		calls++
+		if b[0] == 2 {
+			return errBoom
+		}
+		return nil
+	})
+	if err != errBoom || calls != 2 {
+		t.Errorf("ForEachBatchCtx error: expected boom after 2 calls, got %v after %d", err, calls)
+	}
+}
+
+func TestForEachBatchCtx_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	err := stream.Range(0, 10).ForEachBatchCtx(ctx, 2, func(context.Context, []int) error {
+		calls++
+		cancel()
+		return nil
+	})
+	if err != context.Canceled || calls != 1 {
+		t.Errorf("ForEachBatchCtx cancel: expected Canceled after 1 call, got %v after %d", err, calls)
+	}
+}
+
+func TestForEachBatchCtx_CancelMidBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	rare := stream.Naturals().Peek(func(n int) {
+		if n == 5000 {
+			cancel()
+		}
+	}).Filter(func(n int) bool { return n%1000 == 0 })
+	err := rare.ForEachBatchCtx(ctx, 1000, func(context.Context, []int) error {
+		calls++
+		return nil
+	})
+	if err != context.Canceled || calls != 0 {
+		t.Errorf("ForEachBatchCtx mid-batch cancel: expected Canceled with no calls, got %v after %d", err, calls)
+	}
+}
+
+func TestForEachBatchCtx_CancelBeforeFinalFlush(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	src := stream.Collect(func(yield func(int) bool) {
+		if yield(1) {
+			cancel()
+		}
+	})
+	called := false
+	err := src.ForEachBatchCtx(ctx, 10, func(context.Context, []int) error {
+		called = true
+		return nil
+	})
+	if err != context.Canceled || called {
+		t.Errorf("ForEachBatchCtx final flush: expected Canceled without a call, got %v called=%v", err, called)
+	}
+}
+
+func TestForEachBatchCtx_InvalidSize(t *testing.T) {
+	called := false
+	err := stream.Of(1, 2).ForEachBatchCtx(context.Background(), 0, func(context.Context, []int) error {
+		called = true
+		return nil
+	})
+	if err != nil || called {
+		t.Errorf("ForEachBatchCtx(0): expected no-op, got err=%v called=%v", err, called)
+	}
+	err = stream.Of[int]().ForEachBatchCtx(context.Background(), 2, func(context.Context, []int) error {
+		called = true
+		return nil
+	})
+	if err != nil || called {
+		t.Errorf("ForEachBatchCtx empty: expected no calls, got err=%v called=%v", err, called)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Transform function tests (type-changing)
+// ---------------------------------------------------------------------------
+
+func TestMap(t *testing.T) {
+	result := stream.Map(
 		stream.Of(1, 2, 3),
 		func(n int) string { return fmt.Sprintf("item_%d", n) },
 	).ToSlice()
 
-	if result[0] != "item_1" || result[2] != "item_3" {
-		t.Errorf("Map: unexpected %v", result)
+	if result[0] != "item_1" || result[2] != "item_3" {
+		t.Errorf("Map: unexpected %v", result)
+	}
+}
+
+func TestMapIndexed(t *testing.T) {
+	result := stream.MapIndexed(
+		stream.Of("a", "b", "c"),
+		func(i int, s string) string { return fmt.Sprintf("%d:%s", i, s) },
+	).ToSlice()
+
+	if result[0] != "0:a" || result[2] != "2:c" {
+		t.Errorf("MapIndexed: unexpected %v", result)
+	}
+}
+
+func TestFlatMap(t *testing.T) {
+	users := stream.Of(
+		User{Name: "Alice", Orders: []Order{{Product: "A"}, {Product: "B"}}},
+		User{Name: "Bob", Orders: []Order{{Product: "C"}}},
+	)
+
+	orders := stream.FlatMap(users, func(u User) []Order { return u.Orders })
+	if orders.Count() != 3 {
+		t.Errorf("FlatMap: expected 3 orders, got %d", orders.Count())
+	}
+}
+
+func TestFlatMapIndexed(t *testing.T) {
+	result := stream.FlatMapIndexed(stream.Of("ab", "", "c"), func(i int, s string) []string {
+		var out []string
+		for _, r := range s {
+			out = append(out, fmt.Sprintf("%d%c", i, r))
+		}
+		return out
+	}).ToSlice()
+	if !slices.Equal(result, []string{"0a", "0b", "2c"}) {
+		t.Errorf("FlatMapIndexed: expected [0a 0b 2c], got %v", result)
+	}
+}
+
+func TestFlatMapIndexed_EarlyBreak(t *testing.T) {
+	result := stream.FlatMapIndexed(stream.Naturals(), func(i, n int) []int { return []int{i, n * 10} }).Take(3).ToSlice()
+	if !slices.Equal(result, []int{0, 0, 1}) {
+		t.Errorf("FlatMapIndexed early break: expected [0 0 1], got %v", result)
+	}
+}
+
+func TestToSortedSliceBy(t *testing.T) {
+	result := stream.ToSortedSliceBy(stream.Of(
+		Product{Name: "Laptop", Price: 1200},
+		Product{Name: "Mouse", Price: 25},
+		Product{Name: "Keyboard", Price: 75},
+	), func(p Product) float64 { return p.Price })
+
+	if len(result) != 3 || result[0].Name != "Mouse" || result[1].Name != "Keyboard" || result[2].Name != "Laptop" {
+		t.Errorf("ToSortedSliceBy: unexpected %v", result)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	products := stream.Of(
+		Product{Name: "Laptop", Category: "Electronics", Price: 1200},
+		Product{Name: "T-Shirt", Category: "Clothing", Price: 25},
+		Product{Name: "Keyboard", Category: "Electronics", Price: 75},
+	)
+
+	groups := stream.GroupBy(products, func(p Product) string { return p.Category })
+
+	if len(groups["Electronics"]) != 2 {
+		t.Errorf("GroupBy: expected 2 Electronics products")
+	}
+	if len(groups["Clothing"]) != 1 {
+		t.Errorf("GroupBy: expected 1 Clothing product")
+	}
+}
+
+func TestZip(t *testing.T) {
+	names := stream.Of("Alice", "Bob", "Charlie")
+	scores := stream.Of(85.0, 92.0, 78.0)
+
+	pairs := stream.Zip(names, scores).ToSlice()
+	if len(pairs) != 3 || pairs[0].First != "Alice" || pairs[0].Second != 85.0 {
+		t.Errorf("Zip: unexpected %v", pairs)
+	}
+}
+
+func TestZipUnevenLengths(t *testing.T) {
+	s1 := stream.Of(1, 2, 3, 4, 5)
+	s2 := stream.Of("a", "b")
+
+	pairs := stream.Zip(s1, s2).ToSlice()
+	if len(pairs) != 2 {
+		t.Errorf("Zip uneven: expected 2, got %d", len(pairs))
+	}
+}
+
+func TestAssociate(t *testing.T) {
+	users := stream.Of(
+		User{Name: "Alice", Age: 30},
+		User{Name: "Bob", Age: 25},
+	)
+
+	nameToAge := stream.Associate(users, func(u User) (string, int) {
+		return u.Name, u.Age
+	})
+
+	if nameToAge["Alice"] != 30 || nameToAge["Bob"] != 25 {
+		t.Errorf("Associate: unexpected %v", nameToAge)
+	}
+}
+
+func TestAssociateBy(t *testing.T) {
+	byName := stream.AssociateBy(stream.Of(
+		User{Name: "Alice", Age: 30},
+		User{Name: "Bob", Age: 25},
+		User{Name: "Alice", Age: 31},
+	), func(u User) string { return u.Name })
+	if len(byName) != 2 || byName["Alice"].Age != 31 || byName["Bob"].Age != 25 {
+		t.Errorf("AssociateBy: unexpected %v", byName)
+	}
+}
+
+func TestAssociateWith(t *testing.T) {
+	lengths := stream.AssociateWith(stream.Of("go", "rust", "go"), func(w string) int { return len(w) })
+	if len(lengths) != 2 || lengths["go"] != 2 || lengths["rust"] != 4 {
+		t.Errorf("AssociateWith: unexpected %v", lengths)
+	}
+}
+
+func TestReduceTypeChanging(t *testing.T) {
+	total := stream.Reduce(
+		stream.Of(
+			Order{Amount: 100, Discount: 0.1},
+			Order{Amount: 200, Discount: 0.2},
+			Order{Amount: 300, Discount: 0.0},
+		),
+		0.0,
+		func(acc float64, o Order) float64 {
+			return acc + o.Amount*(1-o.Discount)
+		},
+	)
+
+	expected := 90.0 + 160.0 + 300.0
+	if total != expected {
+		t.Errorf("Reduce: expected %.1f, got %.1f", expected, total)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	result := stream.Flatten(
+		stream.Of([]int{1, 2}, []int{3, 4}, []int{5}),
+	).ToSlice()
+	if len(result) != 5 || result[0] != 1 || result[4] != 5 {
+		t.Errorf("Flatten: unexpected %v", result)
+	}
+}
+
+func TestConcatStreams(t *testing.T) {
+	result := stream.ConcatStreams(stream.Of(
+		stream.Of(1, 2),
+		stream.Of[int](),
+		stream.Range(3, 6),
+	)).ToSlice()
+	if !slices.Equal(result, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("ConcatStreams: expected [1 2 3 4 5], got %v", result)
+	}
+}
+
+func TestConcatStreams_EarlyBreak(t *testing.T) {
+	thirdIterated := false
+	third := stream.Of(7, 8).Peek(func(int) { thirdIterated = true })
+	result := stream.ConcatStreams(stream.Of(stream.Of(1, 2), stream.Of(3, 4), third)).Take(3).ToSlice()
+	if !slices.Equal(result, []int{1, 2, 3}) {
+		t.Errorf("ConcatStreams early break: expected [1 2 3], got %v", result)
+	}
+	if thirdIterated {
+		t.Error("ConcatStreams early break: third inner stream should not be iterated")
+	}
+}
+
+func TestToMap(t *testing.T) {
+	m := stream.ToMap(stream.Zip(
+		stream.Of("a", "b", "c"),
+		stream.Of(1, 2, 3),
+	))
+	if m["a"] != 1 || m["c"] != 3 {
+		t.Errorf("ToMap: unexpected %v", m)
+	}
+}
+
+func TestToMapWith(t *testing.T) {
+	pairs := stream.Zip(stream.Of("a", "b", "a", "a"), stream.Of(1, 2, 3, 4))
+	sums := stream.ToMapWith(pairs, func(a, b int) int { return a + b })
+	if len(sums) != 2 || sums["a"] != 8 || sums["b"] != 2 {
+		t.Errorf("ToMapWith sum: unexpected %v", sums)
+	}
+	first := stream.ToMapWith(pairs, func(existing, _ int) int { return existing })
+	if first["a"] != 1 {
+		t.Errorf("ToMapWith keep-first: expected 1, got %d", first["a"])
+	}
+	if last := stream.ToMap(pairs); last["a"] != 4 {
+		t.Errorf("ToMap: expected last value 4, got %d", last["a"])
+	}
+}
+
+func TestToSet(t *testing.T) {
+	set := stream.ToSet(stream.Of(1, 2, 2, 3, 1))
+	if len(set) != 3 {
+		t.Errorf("ToSet: expected 3 elements, got %v", set)
+	}
+	if _, ok := set[2]; !ok {
+		t.Error("ToSet: expected 2 to be present")
+	}
+	if empty := stream.ToSet(stream.Of[string]()); empty == nil || len(empty) != 0 {
+		t.Errorf("ToSet empty: expected non-nil empty map, got %v", empty)
+	}
+}
+
+func TestEnumerate(t *testing.T) {
+	result := stream.Enumerate(stream.Of("a", "b", "c")).ToSlice()
+	if len(result) != 3 || result[0].First != 0 || result[0].Second != "a" {
+		t.Errorf("Enumerate: unexpected %v", result)
+	}
+	if result[2].First != 2 || result[2].Second != "c" {
+		t.Errorf("Enumerate: unexpected last %v", result[2])
+	}
+}
+
+func TestScanPairs(t *testing.T) {
+	result := stream.ScanPairs(stream.Of(10, 20, 30), 0, func(acc, n int) int { return acc + n }).ToSlice()
+	expected := []stream.Pair[int, int]{{First: 10, Second: 10}, {First: 20, Second: 30}, {First: 30, Second: 60}}
+	if len(result) != len(expected) {
+		t.Fatalf("ScanPairs: expected %d pairs, got %v", len(expected), result)
+	}
+	for i, p := range result {
+		if p != expected[i] {
+			t.Errorf("ScanPairs: expected %v at %d, got %v", expected[i], i, p)
+		}
+	}
+}
+
+func TestScanPairs_EarlyBreak(t *testing.T) {
+	v, ok := stream.ScanPairs(stream.Of(1, 2, 3), 0, func(acc, n int) int { return acc + n }).First()
+	if !ok || v.First != 1 || v.Second != 1 {
+		t.Errorf("ScanPairs early break: unexpected %v", v)
+	}
+}
+
+func TestGroupReduceStream(t *testing.T) {
+	orders := stream.Of(
+		Order{UserID: 2, Amount: 10},
+		Order{UserID: 1, Amount: 5},
+		Order{UserID: 2, Amount: 30},
+		Order{UserID: 3, Amount: 7},
+		Order{UserID: 1, Amount: 15},
+	)
+	result := stream.GroupReduceStream(orders,
+		func(o Order) int { return o.UserID },
+		0.0,
+		func(acc float64, o Order) float64 { return acc + o.Amount },
+	).ToSlice()
+
+	expected := []stream.Pair[int, float64]{{First: 2, Second: 40}, {First: 1, Second: 20}, {First: 3, Second: 7}}
+	if len(result) != len(expected) {
+		t.Fatalf("GroupReduceStream: expected %d groups, got %v", len(expected), result)
+	}
+	for i, p := range result {
+		if p != expected[i] {
+			t.Errorf("GroupReduceStream: expected %v at %d, got %v", expected[i], i, p)
+		}
+	}
+}
+
+func TestGroupReduceStream_EarlyBreak(t *testing.T) {
+	v, ok := stream.GroupReduceStream(stream.Of("b", "a", "b"),
+		func(s string) string { return s },
+		0,
+		func(acc int, _ string) int { return acc + 1 },
+	).First()
+	if !ok || v.First != "b" || v.Second != 2 {
+		t.Errorf("GroupReduceStream early break: unexpected %v", v)
+	}
+}
+
+func TestMaxNMinN(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	data := make([]int, 500)
+	for i := range data {
+		data[i] = r.Intn(1000)
+	}
+	less := func(a, b int) bool { return a < b }
+
+	sorted := slices.Clone(data)
+	slices.Sort(sorted)
+
+	wantMax := slices.Clone(sorted[len(sorted)-10:])
+	slices.Reverse(wantMax)
+	maxN := stream.MaxN(stream.From(data), 10, less)
+	if !slices.Equal(maxN, wantMax) {
+		t.Errorf("MaxN: expected %v, got %v", wantMax, maxN)
+	}
+
+	minN := stream.MinN(stream.From(data), 10, less)
+	if !slices.Equal(minN, sorted[:10]) {
+		t.Errorf("MinN: expected %v, got %v", sorted[:10], minN)
+	}
+}
+
+func TestMaxNMinN_MoreThanLength(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	if result := stream.MaxN(stream.Of(3, 1, 2), 10, less); !slices.Equal(result, []int{3, 2, 1}) {
+		t.Errorf("MaxN(>len): expected [3 2 1], got %v", result)
+	}
+	if result := stream.MinN(stream.Of(3, 1, 2), 10, less); !slices.Equal(result, []int{1, 2, 3}) {
+		t.Errorf("MinN(>len): expected [1 2 3], got %v", result)
+	}
+}
+
+func TestMaxN_ZeroAndEmpty(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	if result := stream.MaxN(stream.Of(1, 2, 3), 0, less); result == nil || len(result) != 0 {
+		t.Errorf("MaxN(0): expected non-nil empty slice, got %v", result)
+	}
+	if result := stream.MinN(stream.Of[int](), 3, less); result == nil || len(result) != 0 {
+		t.Errorf("MinN empty: expected non-nil empty slice, got %v", result)
+	}
+}
+
+func TestWeightedSample(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	items := stream.Of("heavy", "light", "zero")
+	weight := func(s string) float64 {
+		switch s {
+		case "heavy":
+			return 9
+		case "light":
+			return 1
+		}
+		return 0
+	}
+
+	counts := map[string]int{}
+	const runs = 2000
+	for range runs {
+		sample := stream.WeightedSampleWithRand(items, 1, weight, r)
+		if len(sample) != 1 {
+			t.Fatalf("WeightedSample: expected 1 element, got %v", sample)
+		}
+		counts[sample[0]]++
+	}
+	// Expected ratio is 9:1; allow generous slack for randomness.
+	if counts["heavy"] < runs*8/10 || counts["light"] < runs/20 {
+		t.Errorf("WeightedSample: unexpected distribution %v", counts)
+	}
+	if counts["zero"] != 0 {
+		t.Errorf("WeightedSample: zero-weight element selected %d times", counts["zero"])
+	}
+}
+
+func TestWeightedSample_Size(t *testing.T) {
+	sample := stream.WeightedSample(stream.Range(0, 100), 10, func(int) float64 { return 1 })
+	if len(sample) != 10 || len(stream.CollectDistinct(slices.Values(sample)).ToSlice()) != 10 {
+		t.Errorf("WeightedSample: expected 10 distinct elements, got %v", sample)
+	}
+	sample = stream.WeightedSample(stream.Range(0, 3), 10, func(int) float64 { return 1 })
+	if len(sample) != 3 {
+		t.Errorf("WeightedSample(>len): expected 3 elements, got %v", sample)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tokens := stream.Of("\n", "let", "x", "\n", "\n", "print", "x", "\n", "end")
+	result := stream.Split(tokens, func(t string) bool { return t == "\n" }).ToSlice()
+	expected := [][]string{{"let", "x"}, {"print", "x"}, {"end"}}
+	if len(result) != len(expected) {
+		t.Fatalf("Split: expected %v, got %v", expected, result)
+	}
+	for i := range expected {
+		if !slices.Equal(result[i], expected[i]) {
+			t.Errorf("Split: expected %v at %d, got %v", expected[i], i, result[i])
+		}
+	}
+}
+
+func TestSplitKeepEmpty(t *testing.T) {
+	tokens := stream.Of("a", "\n", "\n", "b", "\n")
+	result := stream.SplitKeepEmpty(tokens, func(t string) bool { return t == "\n" }).ToSlice()
+	expected := [][]string{{"a"}, {}, {"b"}}
+	if len(result) != len(expected) {
+		t.Fatalf("SplitKeepEmpty: expected %v, got %v", expected, result)
+	}
+	for i := range expected {
+		if !slices.Equal(result[i], expected[i]) {
+			t.Errorf("SplitKeepEmpty: expected %v at %d, got %v", expected[i], i, result[i])
+		}
+	}
+}
+
+func TestSplit_EarlyBreak(t *testing.T) {
+	evaluated := 0
+	v, ok := stream.Split(
+		stream.Of(1, 2, 0, 3, 4, 0, 5).Peek(func(int) { evaluated++ }),
+		func(n int) bool { return n == 0 },
+	).First()
+	if !ok || !slices.Equal(v, []int{1, 2}) {
+		t.Errorf("Split early break: expected [1 2], got %v", v)
+	}
+	if evaluated != 3 {
+		t.Errorf("Split early break: expected 3 evaluations, got %d", evaluated)
+	}
+}
+
+func TestMapMemo(t *testing.T) {
+	calls := map[string]int{}
+	result := stream.MapMemo(
+		stream.Of("a", "b", "a", "a", "c", "b", "a"),
+		func(s string) string {
+			calls[s]++
+			return strings.ToUpper(s)
+		},
+	).ToSlice()
+
+	expected := []string{"A", "B", "A", "A", "C", "B", "A"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("MapMemo: expected %v, got %v", expected, result)
+	}
+	for k, n := range calls {
+		if n != 1 {
+			t.Errorf("MapMemo: fn(%q) called %d times, expected 1", k, n)
+		}
+	}
+	if len(calls) != 3 {
+		t.Errorf("MapMemo: expected 3 distinct calls, got %v", calls)
+	}
+}
+
+func TestMapMemo_EarlyBreak(t *testing.T) {
+	v, ok := stream.MapMemo(stream.Of(1, 2, 1), func(n int) int { return n * 10 }).First()
+	if !ok || v != 10 {
+		t.Errorf("MapMemo early break: expected 10, got %d", v)
+	}
+}
+
+func TestHeavyHitters(t *testing.T) {
+	// "a" and "b" dominate a long tail of unique values.
+	var data []string
+	for i := range 300 {
+		data = append(data, "a", "b", "a", fmt.Sprintf("tail-%d", i))
+	}
+	result := stream.HeavyHitters(stream.From(data), 3)
+
+	if len(result) == 0 || len(result) > 3 {
+		t.Fatalf("HeavyHitters: expected 1-3 entries, got %v", result)
+	}
+	if result[0].First != "a" {
+		t.Errorf("HeavyHitters: expected 'a' first, got %v", result)
+	}
+	found := map[string]bool{}
+	for i, p := range result {
+		found[p.First] = true
+		if i > 0 && p.Second > result[i-1].Second {
+			t.Errorf("HeavyHitters: results not ordered by count: %v", result)
+		}
+	}
+	if !found["b"] {
+		t.Errorf("HeavyHitters: expected 'b' in output, got %v", result)
+	}
+	// Reported counts never exceed the true frequency.
+	if result[0].Second > 600 {
+		t.Errorf("HeavyHitters: count for 'a' overestimated: %d", result[0].Second)
+	}
+}
+
+func TestHeavyHitters_TieOrder(t *testing.T) {
+	expected := []stream.Pair[string, int]{{First: "c", Second: 2}, {First: "a", Second: 2}, {First: "b", Second: 2}}
+	for range 20 {
+		result := stream.HeavyHitters(stream.Of("c", "a", "b", "b", "a", "c"), 3)
+		if !slices.Equal(result, expected) {
+			t.Fatalf("HeavyHitters ties: expected first-seen order %v, got %v", expected, result)
+		}
+	}
+}
+
+func TestHeavyHitters_ZeroK(t *testing.T) {
+	result := stream.HeavyHitters(stream.Of(1, 1, 2), 0)
+	if result == nil || len(result) != 0 {
+		t.Errorf("HeavyHitters(0): expected non-nil empty slice, got %v", result)
+	}
+}
+
+func TestDistinctWithIndex(t *testing.T) {
+	result := stream.DistinctWithIndex(
+		stream.Of("go", "rust", "go", "zig", "rust", "c"),
+		func(s string) string { return s },
+	).ToSlice()
+	expected := []stream.Pair[int, string]{
+		{First: 0, Second: "go"},
+		{First: 1, Second: "rust"},
+		{First: 3, Second: "zig"},
+		{First: 5, Second: "c"},
+	}
+	if !slices.Equal(result, expected) {
+		t.Errorf("DistinctWithIndex: expected %v, got %v", expected, result)
+	}
+}
+
+func TestDistinctWithIndex_EarlyBreak(t *testing.T) {
+	v, ok := stream.DistinctWithIndex(stream.Of(5, 5, 6), func(n int) int { return n }).First()
+	if !ok || v.First != 0 || v.Second != 5 {
+		t.Errorf("DistinctWithIndex early break: unexpected %v", v)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	onlyA, onlyB := stream.Diff(
+		stream.Of(1, 2, 3, 2, 4, 5),
+		stream.Of(4, 6, 1, 7, 6),
+	)
+	if !slices.Equal(onlyA, []int{2, 3, 5}) {
+		t.Errorf("Diff: expected onlyA [2 3 5], got %v", onlyA)
+	}
+	if !slices.Equal(onlyB, []int{6, 7}) {
+		t.Errorf("Diff: expected onlyB [6 7], got %v", onlyB)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := stream.Of(1, 2, 2, 3, 4, 1)
+	b := stream.Of(3, 4, 4, 5, 6)
+	if got := stream.Intersect(a, b).ToSlice(); !slices.Equal(got, []int{3, 4}) {
+		t.Errorf("Intersect: expected [3 4], got %v", got)
+	}
+	if got := stream.Union(a, b).ToSlice(); !slices.Equal(got, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("Union: expected [1 2 3 4 5 6], got %v", got)
+	}
+	if got := stream.Difference(a, b).ToSlice(); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Difference: expected [1 2], got %v", got)
+	}
+	if got := stream.Intersect(a, stream.Of[int]()).ToSlice(); len(got) != 0 {
+		t.Errorf("Intersect with empty: expected empty, got %v", got)
+	}
+}
+
+func TestSetOperations_EarlyBreak(t *testing.T) {
+	b := stream.Of(0, 2, 4, 6, 8)
+	if got := stream.Intersect(stream.Naturals(), b).Take(3).ToSlice(); !slices.Equal(got, []int{0, 2, 4}) {
+		t.Errorf("Intersect early break: expected [0 2 4], got %v", got)
+	}
+	if got := stream.Difference(stream.Naturals(), b).Take(3).ToSlice(); !slices.Equal(got, []int{1, 3, 5}) {
+		t.Errorf("Difference early break: expected [1 3 5], got %v", got)
+	}
+	if got := stream.Union(stream.Of(1, 2), stream.Naturals()).Take(4).ToSlice(); !slices.Equal(got, []int{1, 2, 0, 3}) {
+		t.Errorf("Union early break: expected [1 2 0 3], got %v", got)
+	}
+}
+
+func TestDiffBy(t *testing.T) {
+	onlyA, onlyB := stream.DiffBy(
+		stream.Of(User{Name: "alice"}, User{Name: "bob"}),
+		stream.Of(User{Name: "Bob"}, User{Name: "carol"}),
+		func(u User) string { return strings.ToLower(u.Name) },
+	)
+	if len(onlyA) != 1 || onlyA[0].Name != "alice" {
+		t.Errorf("DiffBy: unexpected onlyA %v", onlyA)
+	}
+	if len(onlyB) != 1 || onlyB[0].Name != "carol" {
+		t.Errorf("DiffBy: unexpected onlyB %v", onlyB)
+	}
+}
+
+func TestDiff_Identical(t *testing.T) {
+	onlyA, onlyB := stream.Diff(stream.Of("x", "y"), stream.Of("y", "x"))
+	if onlyA == nil || onlyB == nil || len(onlyA) != 0 || len(onlyB) != 0 {
+		t.Errorf("Diff identical: expected two empty slices, got %v %v", onlyA, onlyB)
+	}
+}
+
+func TestToOrderedPairs(t *testing.T) {
+	result := stream.ToOrderedPairs(stream.Of(
+		Order{Product: "pen", Amount: 1},
+		Order{Product: "ink", Amount: 2},
+		Order{Product: "pen", Amount: 3},
+		Order{Product: "pad", Amount: 4},
+	), func(o Order) (string, float64) { return o.Product, o.Amount })
+
+	expected := []stream.Pair[string, float64]{
+		{First: "pen", Second: 3},
+		{First: "ink", Second: 2},
+		{First: "pad", Second: 4},
+	}
+	if !slices.Equal(result, expected) {
+		t.Errorf("ToOrderedPairs: expected %v, got %v", expected, result)
+	}
+}
+
+func TestToOrderedPairs_Empty(t *testing.T) {
+	result := stream.ToOrderedPairs(stream.Of[int](), func(n int) (int, int) { return n, n })
+	if result == nil || len(result) != 0 {
+		t.Errorf("ToOrderedPairs empty: expected non-nil empty slice, got %v", result)
+	}
+}
+
+func TestEqualUnordered(t *testing.T) {
+	if !stream.EqualUnordered(stream.Of(1, 2, 2, 3), stream.Of(2, 3, 1, 2)) {
+		t.Error("EqualUnordered: equal multisets in different order should match")
+	}
+	if stream.EqualUnordered(stream.Of(1, 1, 2), stream.Of(1, 2, 2)) {
+		t.Error("EqualUnordered: {1,1,2} and {1,2,2} should differ")
+	}
+	if stream.EqualUnordered(stream.Of(1, 2), stream.Of(1, 2, 3)) {
+		t.Error("EqualUnordered: different lengths should differ")
+	}
+	if stream.EqualUnordered(stream.Of(1, 2, 3), stream.Of(1, 2)) {
+		t.Error("EqualUnordered: different lengths should differ")
+	}
+}
+
+func TestEqualUnorderedBy(t *testing.T) {
+	a := stream.Of(User{Name: "Alice"}, User{Name: "bob"})
+	b := stream.Of(User{Name: "BOB"}, User{Name: "alice"})
+	if !stream.EqualUnorderedBy(a, b, func(u User) string { return strings.ToLower(u.Name) }) {
+		t.Error("EqualUnorderedBy: expected case-insensitive match")
+	}
+}
+
+func TestCompact(t *testing.T) {
+	result := stream.Compact(stream.Of(1, 1, 2, 2, 2, 1, 3, 3)).ToSlice()
+	if !slices.Equal(result, []int{1, 2, 1, 3}) {
+		t.Errorf("Compact: expected [1 2 1 3], got %v", result)
+	}
+	if result := stream.Compact(stream.Of[string]()).ToSlice(); len(result) != 0 {
+		t.Errorf("Compact empty: expected empty, got %v", result)
+	}
+}
+
+func TestCompactBy(t *testing.T) {
+	result := stream.CompactBy(stream.Of("apple", "avocado", "banana", "blueberry", "apricot"), func(s string) byte { return s[0] }).ToSlice()
+	if !slices.Equal(result, []string{"apple", "banana", "apricot"}) {
+		t.Errorf("CompactBy: expected [apple banana apricot], got %v", result)
+	}
+}
+
+func TestCompact_EarlyBreak(t *testing.T) {
+	result := stream.Compact(stream.Map(stream.Naturals(), func(n int) int { return n / 3 })).Take(3).ToSlice()
+	if !slices.Equal(result, []int{0, 1, 2}) {
+		t.Errorf("Compact early break: expected [0 1 2], got %v", result)
+	}
+}
+
+func TestRunLengthEncode(t *testing.T) {
+	result := stream.RunLengthEncode(stream.Of("A", "A", "B", "A", "A", "A")).ToSlice()
+	expected := []stream.Pair[string, int]{{First: "A", Second: 2}, {First: "B", Second: 1}, {First: "A", Second: 3}}
+	if !slices.Equal(result, expected) {
+		t.Errorf("RunLengthEncode: expected %v, got %v", expected, result)
+	}
+	if result := stream.RunLengthEncode(stream.Of[int]()).ToSlice(); len(result) != 0 {
+		t.Errorf("RunLengthEncode empty: expected empty, got %v", result)
+	}
+}
+
+func TestRunLengthEncode_EarlyBreak(t *testing.T) {
+	result := stream.RunLengthEncode(stream.Map(stream.Naturals(), func(n int) int { return n / 2 })).Take(2).ToSlice()
+	expected := []stream.Pair[int, int]{{First: 0, Second: 2}, {First: 1, Second: 2}}
+	if !slices.Equal(result, expected) {
+		t.Errorf("RunLengthEncode early break: expected %v, got %v", expected, result)
+	}
+}
+
+func TestChunkBy(t *testing.T) {
+	result := stream.ChunkBy(stream.Of("A", "A", "B", "A"), func(s string) string { return s })
+	expected := [][]string{{"A", "A"}, {"B"}, {"A"}}
+	if len(result) != len(expected) {
+		t.Fatalf("ChunkBy: expected %v, got %v", expected, result)
+	}
+	for i := range result {
+		if !slices.Equal(result[i], expected[i]) {
+			t.Errorf("ChunkBy: expected %v at %d, got %v", expected[i], i, result[i])
+		}
+	}
+	if empty := stream.ChunkBy(stream.Of[int](), func(n int) int { return n }); empty == nil || len(empty) != 0 {
+		t.Errorf("ChunkBy empty: expected non-nil empty slice, got %v", empty)
+	}
+}
+
+func TestGroupAdjacent(t *testing.T) {
+	orders := stream.Of(
+		Order{UserID: 1, Amount: 10},
+		Order{UserID: 1, Amount: 20},
+		Order{UserID: 2, Amount: 5},
+		Order{UserID: 3, Amount: 1},
+		Order{UserID: 3, Amount: 2},
+		Order{UserID: 3, Amount: 3},
+	)
+	result := stream.GroupAdjacent(orders, func(o Order) int { return o.UserID }).ToSlice()
+	if len(result) != 3 {
+		t.Fatalf("GroupAdjacent: expected 3 groups, got %v", result)
+	}
+	expectedKeys := []int{1, 2, 3}
+	expectedSizes := []int{2, 1, 3}
+	for i, g := range result {
+		if g.First != expectedKeys[i] || len(g.Second) != expectedSizes[i] {
+			t.Errorf("GroupAdjacent: unexpected group %d: key %d size %d", i, g.First, len(g.Second))
+		}
+	}
+}
+
+func TestGroupAdjacent_LazyInfinite(t *testing.T) {
+	evaluated := 0
+	result := stream.GroupAdjacent(
+		stream.Naturals().Peek(func(int) { evaluated++ }),
+		func(n int) int { return n / 3 },
+	).Take(2).ToSlice()
+	if len(result) != 2 || !slices.Equal(result[1].Second, []int{3, 4, 5}) {
+		t.Errorf("GroupAdjacent infinite: unexpected %v", result)
+	}
+	// The second run ends when 6 arrives.
+	if evaluated != 7 {
+		t.Errorf("GroupAdjacent infinite: expected 7 evaluations, got %d", evaluated)
+	}
+}
+
+func TestTumblingWindow(t *testing.T) {
+	sum := func(w []int) int { return stream.Sum(stream.Of(w...)) }
+
+	full := stream.TumblingWindow(stream.Range(1, 11), 3, sum).ToSlice()
+	if !slices.Equal(full, []int{6, 15, 24}) {
+		t.Errorf("TumblingWindow: expected [6 15 24], got %v", full)
+	}
+
+	withPartial := stream.TumblingWindowPartial(stream.Range(1, 11), 3, sum).ToSlice()
+	if !slices.Equal(withPartial, []int{6, 15, 24, 10}) {
+		t.Errorf("TumblingWindowPartial: expected [6 15 24 10], got %v", withPartial)
+	}
+
+	exact := stream.TumblingWindowPartial(stream.Range(1, 7), 3, sum).ToSlice()
+	if !slices.Equal(exact, []int{6, 15}) {
+		t.Errorf("TumblingWindowPartial exact: expected [6 15], got %v", exact)
+	}
+}
+
+func TestTumblingWindow_EdgeCases(t *testing.T) {
+	count := func(w []int) int { return len(w) }
+	if result := stream.TumblingWindow(stream.Range(0, 5), 0, count).ToSlice(); len(result) != 0 {
+		t.Errorf("TumblingWindow(0): expected empty, got %v", result)
+	}
+	result := stream.TumblingWindow(stream.Naturals(), 4, count).Take(2).ToSlice()
+	if !slices.Equal(result, []int{4, 4}) {
+		t.Errorf("TumblingWindow early break: expected [4 4], got %v", result)
+	}
+}
+
+func TestZipApply(t *testing.T) {
+	result := stream.ZipApply(stream.Of("a", "b", "c", "d"), func(i int) int { return i * i }).ToSlice()
+	expected := []stream.Pair[string, int]{
+		{First: "a", Second: 0},
+		{First: "b", Second: 1},
+		{First: "c", Second: 4},
+		{First: "d", Second: 9},
+	}
+	if !slices.Equal(result, expected) {
+		t.Errorf("ZipApply: expected %v, got %v", expected, result)
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	primary := stream.Of("", "b1", "", "", "e1")
+	fallback := stream.Of("a2", "b2", "", "d2", "e2", "f2")
+	defaults := stream.Of("a3", "b3", "c3", "d3", "e3", "f3")
+
+	result := stream.Coalesce(primary, fallback, defaults).ToSlice()
+	expected := []string{"a2", "b1", "c3", "d2", "e1"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("Coalesce: expected %v, got %v", expected, result)
+	}
+}
+
+func TestCoalesceFunc(t *testing.T) {
+	present := func(n int) bool { return n >= 0 }
+	result := stream.CoalesceFunc(present,
+		stream.Of(-1, 0, -1),
+		stream.Of(-1, 5, -1),
+	).ToSlice()
+	// 0 counts as present; an all-missing position yields the zero value.
+	if !slices.Equal(result, []int{0, 0, 0}) {
+		t.Errorf("CoalesceFunc: expected [0 0 0], got %v", result)
+	}
+
+	result = stream.CoalesceFunc(present, stream.Of(-1, 2), stream.Of(7, 8)).ToSlice()
+	if !slices.Equal(result, []int{7, 2}) {
+		t.Errorf("CoalesceFunc: expected [7 2], got %v", result)
+	}
+}
+
+func TestCoalesce_EdgeCases(t *testing.T) {
+	if result := stream.Coalesce[int]().ToSlice(); len(result) != 0 {
+		t.Errorf("Coalesce(): expected empty, got %v", result)
+	}
+	v, ok := stream.Coalesce(stream.Naturals(), stream.Repeat(9)).First()
+	if !ok || v != 9 {
+		t.Errorf("Coalesce early break: expected 9, got %d", v)
+	}
+}
+
+func TestJoinString(t *testing.T) {
+	if got := stream.JoinString(stream.Of("a", "b", "c"), ", "); got != "a, b, c" {
+		t.Errorf("JoinString: expected %q, got %q", "a, b, c", got)
+	}
+	if got := stream.JoinString(stream.Of("solo"), "-"); got != "solo" {
+		t.Errorf("JoinString single: expected %q, got %q", "solo", got)
+	}
+	if got := stream.JoinString(stream.Of[string](), "-"); got != "" {
+		t.Errorf("JoinString empty: expected empty string, got %q", got)
+	}
+}
+
+func TestGroupByThen(t *testing.T) {
+	totals := stream.GroupByThen(stream.Of(
+		Product{Name: "Laptop", Price: 1200, Category: "Electronics"},
+		Product{Name: "Desk", Price: 300, Category: "Furniture"},
+		Product{Name: "Mouse", Price: 25, Category: "Electronics"},
+	), func(p Product) string { return p.Category }, func(g []Product) float64 {
+		return stream.SumBy(stream.Of(g...), func(p Product) float64 { return p.Price })
+	})
+	if len(totals) != 2 || totals["Electronics"] != 1225 || totals["Furniture"] != 300 {
+		t.Errorf("GroupByThen: unexpected %v", totals)
+	}
+	empty := stream.GroupByThen(stream.Of[int](), func(n int) int { return n }, func(g []int) int { return len(g) })
+	if empty == nil || len(empty) != 0 {
+		t.Errorf("GroupByThen empty: expected non-nil empty map, got %v", empty)
+	}
+}
+
+func TestTally(t *testing.T) {
+	counts := stream.Tally(stream.Of("a", "b", "a", "c", "a"))
+	if len(counts) != 3 || counts["a"] != 3 || counts["b"] != 1 || counts["c"] != 1 {
+		t.Errorf("Tally: unexpected %v", counts)
+	}
+	if empty := stream.Tally(stream.Of[int]()); empty == nil || len(empty) != 0 {
+		t.Errorf("Tally empty: expected non-nil empty map, got %v", empty)
+	}
+}
+
+func TestTallyBy(t *testing.T) {
+	counts := stream.TallyBy(stream.Of("apple", "avocado", "banana"), func(s string) byte { return s[0] })
+	if len(counts) != 2 || counts['a'] != 2 || counts['b'] != 1 {
+		t.Errorf("TallyBy: unexpected %v", counts)
+	}
+}
+
+func TestMode(t *testing.T) {
+	if m, ok := stream.Mode(stream.Of(3, 1, 3, 2, 1, 3)); !ok || m != 3 {
+		t.Errorf("Mode: expected 3, got %v, %v", m, ok)
+	}
+	if m, ok := stream.Mode(stream.Of("a", "b", "b", "a")); !ok || m != "b" {
+		t.Errorf("Mode tie: expected b (first to reach max), got %v, %v", m, ok)
+	}
+	if _, ok := stream.Mode(stream.Of[int]()); ok {
+		t.Error("Mode empty: expected false")
+	}
+}
+
+func TestGroupBySortedValues(t *testing.T) {
+	groups := stream.GroupBySortedValues(stream.Of(
+		Product{Name: "Laptop", Category: "Electronics", Price: 1200},
+		Product{Name: "Jeans", Category: "Clothing", Price: 80},
+		Product{Name: "Mouse", Category: "Electronics", Price: 25},
+		Product{Name: "T-Shirt", Category: "Clothing", Price: 25},
+		Product{Name: "Keyboard", Category: "Electronics", Price: 75},
+	),
+		func(p Product) string { return p.Category },
+		func(a, b Product) int {
+			if a.Price < b.Price {
+				return -1
+			}
+			if a.Price > b.Price {
+				return 1
+			}
+			return 0
+		},
+	)
+
+	names := func(ps []Product) []string {
+		return stream.Map(stream.Of(ps...), func(p Product) string { return p.Name }).ToSlice()
+	}
+	if got := names(groups["Electronics"]); !slices.Equal(got, []string{"Mouse", "Keyboard", "Laptop"}) {
+		t.Errorf("GroupBySortedValues: unexpected Electronics order %v", got)
+	}
+	if got := names(groups["Clothing"]); !slices.Equal(got, []string{"T-Shirt", "Jeans"}) {
+		t.Errorf("GroupBySortedValues: unexpected Clothing order %v", got)
+	}
+}
+
+func TestZipLongest(t *testing.T) {
+	left := stream.ZipLongest(stream.Of(1, 2, 3), stream.Of("a"), 0, "-").ToSlice()
+	expected := []stream.Pair[int, string]{{First: 1, Second: "a"}, {First: 2, Second: "-"}, {First: 3, Second: "-"}}
+	if !slices.Equal(left, expected) {
+		t.Errorf("ZipLongest: expected %v, got %v", expected, left)
+	}
+	right := stream.ZipLongest(stream.Of(1), stream.Of("a", "b", "c"), 0, "-").ToSlice()
+	expected = []stream.Pair[int, string]{{First: 1, Second: "a"}, {First: 0, Second: "b"}, {First: 0, Second: "c"}}
+	if !slices.Equal(right, expected) {
+		t.Errorf("ZipLongest: expected %v, got %v", expected, right)
+	}
+}
+
+func TestZipLongest_EarlyBreak(t *testing.T) {
+	first := stream.ZipLongest(stream.Naturals(), stream.Of("a"), -1, "-").Take(2).ToSlice()
+	if len(first) != 2 || first[1] != (stream.Pair[int, string]{First: 1, Second: "-"}) {
+		t.Errorf("ZipLongest early break: unexpected %v", first)
+	}
+	second := stream.ZipLongest(stream.Of("a"), stream.Naturals(), "-", -1).Take(3).ToSlice()
+	if len(second) != 3 || second[2] != (stream.Pair[string, int]{First: "-", Second: 2}) {
+		t.Errorf("ZipLongest early break: unexpected %v", second)
+	}
+}
+
+func TestUnzip(t *testing.T) {
+	a, b := stream.Unzip(stream.Zip(stream.Of("x", "y"), stream.Of(1, 2)))
+	if !slices.Equal(a, []string{"x", "y"}) || !slices.Equal(b, []int{1, 2}) {
+		t.Errorf("Unzip: unexpected %v %v", a, b)
+	}
+	ea, eb := stream.Unzip(stream.Of[stream.Pair[string, int]]())
+	if ea == nil || eb == nil || len(ea) != 0 || len(eb) != 0 {
+		t.Errorf("Unzip empty: expected two empty slices, got %v %v", ea, eb)
+	}
+}
+
+func TestUnzipToSlices(t *testing.T) {
+	names, scores := stream.UnzipToSlices(stream.Zip(
+		stream.Of("Alice", "Bob", "Charlie"),
+		stream.Of(85.0, 92.0, 78.0),
+	))
+	if !slices.Equal(names, []string{"Alice", "Bob", "Charlie"}) {
+		t.Errorf("UnzipToSlices: unexpected names %v", names)
+	}
+	if !slices.Equal(scores, []float64{85, 92, 78}) {
+		t.Errorf("UnzipToSlices: unexpected scores %v", scores)
+	}
+
+	a, b := stream.UnzipToSlices(stream.Of[stream.Pair[int, int]]())
+	if a == nil || b == nil || len(a) != 0 || len(b) != 0 {
+		t.Errorf("UnzipToSlices empty: expected two empty slices, got %v %v", a, b)
+	}
+}
+
+func TestTryMap(t *testing.T) {
+	parse := func(s string) (int, error) {
+		var n int
+		_, err := fmt.Sscan(s, &n)
+		return n, err
+	}
+	results := stream.TryMap(stream.Of("1", "x", "3"), parse).ToSlice()
+	if len(results) != 3 || results[0].Value != 1 || results[0].Err != nil || results[1].Err == nil || results[2].Value != 3 {
+		t.Errorf("TryMap: unexpected %v", results)
+	}
+
+	nums, err := stream.CollectResults(stream.TryMap(stream.Of("1", "2", "3"), parse))
+	if err != nil || !slices.Equal(nums, []int{1, 2, 3}) {
+		t.Errorf("CollectResults: expected [1 2 3], got %v, %v", nums, err)
+	}
+}
+
+func TestCollectResults_FirstError(t *testing.T) {
+	calls := 0
+	nums, err := stream.CollectResults(stream.TryMap(stream.Naturals(), func(n int) (int, error) {
+		calls++
+		if n == 2 {
+			return 0, fmt.Errorf("bad element %d", n)
+		}
+		return n, nil
+	}))
+	if nums != nil || err == nil || err.Error() != "bad element 2" || calls != 3 {
+		t.Errorf("CollectResults: expected first error after 3 calls, got %v, %v after %d", nums, err, calls)
+	}
+}
+
+func TestCollectResults_Empty(t *testing.T) {
+	nums, err := stream.CollectResults(stream.Of[stream.Result[int]]())
+	if err != nil || nums == nil || len(nums) != 0 {
+		t.Errorf("CollectResults empty: expected non-nil empty slice, got %v, %v", nums, err)
+	}
+}
+
+func TestFilterMap(t *testing.T) {
+	result := stream.FilterMap(stream.Of(1, 2, 3, 4, 5), func(n int) (string, bool) {
+		return fmt.Sprint(n * n), n%2 == 1
+	}).ToSlice()
+	if !slices.Equal(result, []string{"1", "9", "25"}) {
+		t.Errorf("FilterMap: expected [1 9 25], got %v", result)
+	}
+}
+
+func TestFilterMap_EarlyBreak(t *testing.T) {
+	evaluated := 0
+	result := stream.FilterMap(
+		stream.Naturals().Peek(func(int) { evaluated++ }),
+		func(n int) (int, bool) { return n * 10, n%2 == 0 },
+	).Take(3).ToSlice()
+	if !slices.Equal(result, []int{0, 20, 40}) || evaluated != 5 {
+		t.Errorf("FilterMap early break: got %v after %d evaluations", result, evaluated)
+	}
+}
+
+func TestMapWhile(t *testing.T) {
+	pulls := 0
+	src := stream.Of("1", "2", "x", "4").Peek(func(string) { pulls++ })
+	result := stream.MapWhile(src, func(s string) (int, bool) {
+		var n int
+		_, err := fmt.Sscan(s, &n)
+		return n, err == nil
+	}).ToSlice()
+	if !slices.Equal(result, []int{1, 2}) || pulls != 3 {
+		t.Errorf("MapWhile: expected [1 2] after 3 pulls, got %v after %d", result, pulls)
+	}
+}
+
+func TestMapWhile_EarlyBreak(t *testing.T) {
+	result := stream.MapWhile(stream.Naturals(), func(n int) (int, bool) { return n * n, true }).Take(3).ToSlice()
+	if !slices.Equal(result, []int{0, 1, 4}) {
+		t.Errorf("MapWhile early break: expected [0 1 4], got %v", result)
+	}
+}
+
+func TestFilterMapIndexed(t *testing.T) {
+	result := stream.FilterMapIndexed(stream.Of(5, 6, 7, 8, 9), func(i, n int) (int, bool) {
+		return n * 2, i%2 == 0
+	}).ToSlice()
+	if !slices.Equal(result, []int{10, 14, 18}) {
+		t.Errorf("FilterMapIndexed: expected [10 14 18], got %v", result)
+	}
+}
+
+func TestFilterMapIndexed_EarlyBreak(t *testing.T) {
+	evaluated := 0
+	result := stream.FilterMapIndexed(
+		stream.Naturals().Peek(func(int) { evaluated++ }),
+		func(i, n int) (string, bool) { return fmt.Sprint(n), i%3 == 0 },
+	).Take(2).ToSlice()
+	if !slices.Equal(result, []string{"0", "3"}) || evaluated != 4 {
+		t.Errorf("FilterMapIndexed early break: got %v after %d evaluations", result, evaluated)
+	}
+}
+
+func TestReduceUntil(t *testing.T) {
+	evaluated := 0
+	total, early := stream.ReduceUntil(
+		stream.Naturals().Peek(func(int) { evaluated++ }),
+		0.0,
+		func(acc float64, n int) (float64, bool) {
+			acc += float64(n)
+			return acc, acc >= 10
+		},
+	)
+	if total != 10 || !early {
+		t.Errorf("ReduceUntil: expected 10 with early stop, got %f (early=%v)", total, early)
+	}
+	if evaluated != 5 {
+		t.Errorf("ReduceUntil: expected 5 evaluations, got %d", evaluated)
+	}
+}
+
+func TestReduceUntil_Exhausted(t *testing.T) {
+	total, early := stream.ReduceUntil(stream.Of(1, 2, 3), "", func(acc string, n int) (string, bool) {
+		return acc + fmt.Sprint(n), false
+	})
+	if total != "123" || early {
+		t.Errorf("ReduceUntil exhausted: expected '123' without early stop, got %q (early=%v)", total, early)
+	}
+}
+
+func TestScanTypeChanging(t *testing.T) {
+	result := stream.Scan(stream.Of("a", "bb", "ccc"), 0, func(acc int, s string) int {
+		return acc + len(s)
+	}).ToSlice()
+	if !slices.Equal(result, []int{0, 1, 3, 6}) {
+		t.Errorf("Scan type-changing: expected [0 1 3 6], got %v", result)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	result := stream.Batch(stream.Range(1, 8), 3).ToSlice()
+	expected := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}
+	if len(result) != len(expected) {
+		t.Fatalf("Batch: expected %v, got %v", expected, result)
+	}
+	for i := range result {
+		if !slices.Equal(result[i], expected[i]) {
+			t.Errorf("Batch: expected %v at %d, got %v", expected[i], i, result[i])
+		}
+	}
+	if result := stream.Batch(stream.Range(1, 8), 0).ToSlice(); len(result) != 0 {
+		t.Errorf("Batch(0): expected empty, got %v", result)
+	}
+}
+
+func TestBatch_Infinite(t *testing.T) {
+	result := stream.Batch(stream.Naturals(), 100).Take(3).ToSlice()
+	if len(result) != 3 || result[2][0] != 200 || result[2][99] != 299 {
+		t.Errorf("Batch infinite: unexpected batches of lengths %d", len(result))
+	}
+}
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		size, step int
+		expected   [][]int
+	}{
+		{3, 1, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}},
+		{2, 2, [][]int{{1, 2}, {3, 4}}},
+		{2, 3, [][]int{{1, 2}, {4, 5}}},
+		{6, 1, nil},
+	}
+	for _, tt := range tests {
+		result := stream.Window(stream.Range(1, 6), tt.size, tt.step).ToSlice()
+		if len(result) != len(tt.expected) {
+			t.Errorf("Window(%d, %d): expected %v, got %v", tt.size, tt.step, tt.expected, result)
+			continue
+		}
+		for i := range result {
+			if !slices.Equal(result[i], tt.expected[i]) {
+				t.Errorf("Window(%d, %d): expected %v at %d, got %v", tt.size, tt.step, tt.expected[i], i, result[i])
+			}
+		}
+	}
+}
+
+func TestWindow_Invalid(t *testing.T) {
+	if result := stream.Window(stream.Range(1, 6), 0, 1).ToSlice(); len(result) != 0 {
+		t.Errorf("Window(0, 1): expected empty, got %v", result)
+	}
+	if result := stream.Window(stream.Range(1, 6), 2, -1).ToSlice(); len(result) != 0 {
+		t.Errorf("Window(2, -1): expected empty, got %v", result)
+	}
+}
+
+func TestWindow_Infinite(t *testing.T) {
+	result := stream.Window(stream.Naturals(), 3, 2).Take(2).ToSlice()
+	if len(result) != 2 || !slices.Equal(result[0], []int{0, 1, 2}) || !slices.Equal(result[1], []int{2, 3, 4}) {
+		t.Errorf("Window infinite: unexpected %v", result)
+	}
+}
+
+func TestMapWindow(t *testing.T) {
+	result := stream.MapWindow(stream.Range(1, 6), 3, slices.Clone[[]int]).ToSlice()
+	expected := [][]int{{1}, {1, 2}, {1, 2, 3}, {2, 3, 4}, {3, 4, 5}}
+	if len(result) != len(expected) {
+		t.Fatalf("MapWindow: expected %v, got %v", expected, result)
+	}
+	for i := range result {
+		if !slices.Equal(result[i], expected[i]) {
+			t.Errorf("MapWindow: expected %v at %d, got %v", expected[i], i, result[i])
+		}
+	}
+}
+
+func TestMapWindow_Spikes(t *testing.T) {
+	readings := stream.Of(10.0, 10, 10, 50, 10, 10, 60)
+	flags := stream.MapWindow(readings, 3, func(w []float64) bool {
+		return w[len(w)-1] > 1.5*stream.Avg(stream.Of(w...))
+	}).ToSlice()
+	expected := []bool{false, false, false, true, false, false, true}
+	if !slices.Equal(flags, expected) {
+		t.Errorf("MapWindow spikes: expected %v, got %v", expected, flags)
+	}
+}
+
+func TestMapWindow_Invalid(t *testing.T) {
+	if n := stream.MapWindow(stream.Of(1, 2), 0, func(w []int) int { return len(w) }).Count(); n != 0 {
+		t.Errorf("MapWindow(0): expected empty, got %d elements", n)
+	}
+}
+
+func TestMapWindow_EarlyBreak(t *testing.T) {
+	result := stream.MapWindow(stream.Naturals(), 2, func(w []int) int { return w[0] + w[len(w)-1] }).Take(4).ToSlice()
+	if !slices.Equal(result, []int{0, 1, 3, 5}) {
+		t.Errorf("MapWindow early break: expected [0 1 3 5], got %v", result)
+	}
+}
+
+func TestMergeJoin(t *testing.T) {
+	left := stream.Of(1, 2, 2, 4, 6)
+	right := stream.Of("1a", "2a", "2b", "3a", "6a")
+	key := func(s string) int { return int(s[0] - '0') }
+	result := stream.MergeJoin(left, right, func(v int) int { return v }, key).ToSlice()
+	expected := []stream.Pair[int, string]{
+		{First: 1, Second: "1a"},
+		{First: 2, Second: "2a"}, {First: 2, Second: "2b"},
+		{First: 2, Second: "2a"}, {First: 2, Second: "2b"},
+		{First: 6, Second: "6a"},
+	}
+	if !slices.Equal(result, expected) {
+		t.Errorf("MergeJoin: expected %v, got %v", expected, result)
+	}
+}
+
+func TestMergeJoin_RightExhausted(t *testing.T) {
+	var seen []int
+	left := stream.Of(1, 5, 7, 9).Peek(func(v int) { seen = append(seen, v) })
+	result := stream.MergeJoin(left, stream.Of(1, 2), func(v int) int { return v }, func(v int) int { return v }).ToSlice()
+	if len(result) != 1 || result[0] != (stream.Pair[int, int]{First: 1, Second: 1}) {
+		t.Errorf("MergeJoin: expected [{1 1}], got %v", result)
+	}
+	if !slices.Equal(seen, []int{1, 5}) {
+		t.Errorf("MergeJoin: expected left to stop after right is exhausted, saw %v", seen)
+	}
+}
+
+func TestMergeJoin_EarlyBreak(t *testing.T) {
+	id := func(v int) int { return v }
+	result := stream.MergeJoin(stream.Naturals(), stream.Naturals(), id, id).Take(3).ToSlice()
+	expected := []stream.Pair[int, int]{{First: 0, Second: 0}, {First: 1, Second: 1}, {First: 2, Second: 2}}
+	if !slices.Equal(result, expected) {
+		t.Errorf("MergeJoin early break: expected %v, got %v", expected, result)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Numeric function tests
+// ---------------------------------------------------------------------------
+
+func TestSum(t *testing.T) {
+	total := stream.Sum(stream.Of(1, 2, 3, 4, 5))
+	if total != 15 {
+		t.Errorf("Sum: expected 15, got %d", total)
+	}
+}
+
+func TestAvg(t *testing.T) {
+	avg := stream.Avg(stream.Of(10.0, 20.0, 30.0))
+	if avg != 20.0 {
+		t.Errorf("Avg: expected 20.0, got %f", avg)
+	}
+}
+
+func TestSumByAvgBy(t *testing.T) {
+	products := stream.Of(
+		Product{Price: 1200},
+		Product{Price: 150},
+		Product{Price: 75},
+	)
+
+	total := stream.SumBy(products, func(p Product) float64 { return p.Price })
+	if total != 1425 {
+		t.Errorf("SumBy: expected 1425, got %f", total)
+	}
+
+	products2 := stream.Of(
+		Product{Price: 1200},
+		Product{Price: 150},
+		Product{Price: 75},
+	)
+	avg := stream.AvgBy(products2, func(p Product) float64 { return p.Price })
+	expected := 1425.0 / 3.0
+	if avg < expected-0.01 || avg > expected+0.01 {
+		t.Errorf("AvgBy: expected ~%.2f, got %.2f", expected, avg)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	s := stream.Of(3.0, 1.0, 4.0, 1.0, 5.0)
+
+	min, _ := stream.Min(s)
+	if min != 1.0 {
+		t.Errorf("Min: expected 1.0, got %f", min)
+	}
+
+	s2 := stream.Of(3.0, 1.0, 4.0, 1.0, 5.0)
+	max, _ := stream.Max(s2)
+	if max != 5.0 {
+		t.Errorf("Max: expected 5.0, got %f", max)
+	}
+}
+
+func TestClampCount(t *testing.T) {
+	clamped, counts := stream.ClampCount(stream.Of(-5, 10, 50, 120, 99, 101, -1), 0, 100)
+	result := clamped.ToSlice()
+
+	expected := []int{0, 10, 50, 100, 99, 100, 0}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("ClampCount: expected %d at %d, got %d", expected[i], i, v)
+		}
+	}
+	low, high := counts()
+	if low != 2 || high != 2 {
+		t.Errorf("ClampCount: expected counts 2/2, got %d/%d", low, high)
+	}
+
+	// Re-iterating resets the counts rather than accumulating
+	clamped.ToSlice()
+	if low2, high2 := counts(); low2 != 2 || high2 != 2 {
+		t.Errorf("ClampCount: expected counts reset to 2/2, got %d/%d", low2, high2)
+	}
+}
+
+func TestClampCount_EarlyBreak(t *testing.T) {
+	clamped, counts := stream.ClampCount(stream.Of(200, -3, 300), 0, 100)
+	v, ok := clamped.First()
+	if !ok || v != 100 {
+		t.Errorf("ClampCount early break: expected 100, got %d", v)
+	}
+	if low, high := counts(); low != 0 || high != 1 {
+		t.Errorf("ClampCount early break: expected counts 0/1, got %d/%d", low, high)
+	}
+}
+
+func TestDistinctAdjacentByTolerance(t *testing.T) {
+	result := stream.DistinctAdjacentByTolerance(
+		stream.Of(20.0, 20.1, 19.9, 20.4, 25.0, 25.2, 24.8, 20.0),
+		0.5,
+	).ToSlice()
+	expected := []float64{20.0, 25.0, 20.0}
+	if !slices.Equal(result, expected) {
+		t.Errorf("DistinctAdjacentByTolerance: expected %v, got %v", expected, result)
+	}
+}
+
+func TestDistinctAdjacentByTolerance_Unsigned(t *testing.T) {
+	result := stream.DistinctAdjacentByTolerance(stream.Of[uint](10, 9, 11, 5, 6), 1).ToSlice()
+	if !slices.Equal(result, []uint{10, 5}) {
+		t.Errorf("DistinctAdjacentByTolerance unsigned: expected [10 5], got %v", result)
+	}
+}
+
+func TestDistinctAdjacentByTolerance_FullRange(t *testing.T) {
+	result := stream.DistinctAdjacentByTolerance(stream.Of[int8](-128, 127, 126, -128), 1).ToSlice()
+	if !slices.Equal(result, []int8{-128, 127, -128}) {
+		t.Errorf("DistinctAdjacentByTolerance full range: expected [-128 127 -128], got %v", result)
+	}
+	wide := stream.DistinctAdjacentByTolerance(stream.Of[int16](-30000, 30000), 100).ToSlice()
+	if !slices.Equal(wide, []int16{-30000, 30000}) {
+		t.Errorf("DistinctAdjacentByTolerance int16 swing: expected [-30000 30000], got %v", wide)
+	}
+}
+
+func TestDistinctAdjacentByTolerance_Infinite(t *testing.T) {
+	result := stream.DistinctAdjacentByTolerance(stream.Naturals(), 2).Take(3).ToSlice()
+	if !slices.Equal(result, []int{0, 3, 6}) {
+		t.Errorf("DistinctAdjacentByTolerance infinite: expected [0 3 6], got %v", result)
+	}
+}
+
+func TestGeometricMean(t *testing.T) {
+	values := []float64{1.1, 1.5, 0.9, 2.0}
+	g, ok := stream.GeometricMean(stream.From(values))
+	if !ok {
+		t.Fatal("GeometricMean: expected ok")
+	}
+	product := 1.0
+	for _, v := range values {
+		product *= v
+	}
+	expected := math.Pow(product, 1.0/float64(len(values)))
+	if math.Abs(g-expected) > 1e-9 {
+		t.Errorf("GeometricMean: expected %f, got %f", expected, g)
+	}
+
+	g, ok = stream.GeometricMean(stream.Of(2, 8))
+	if !ok || math.Abs(g-4) > 1e-9 {
+		t.Errorf("GeometricMean ints: expected 4, got %f (ok=%v)", g, ok)
+	}
+}
+
+func TestGeometricMeanBy(t *testing.T) {
+	g, ok := stream.GeometricMeanBy(stream.Of(
+		Product{Price: 1},
+		Product{Price: 10},
+		Product{Price: 100},
+	), func(p Product) float64 { return p.Price })
+	if !ok || math.Abs(g-10) > 1e-9 {
+		t.Errorf("GeometricMeanBy: expected 10, got %f (ok=%v)", g, ok)
+	}
+}
+
+func TestGeometricMean_Undefined(t *testing.T) {
+	if _, ok := stream.GeometricMean(stream.Of[float64]()); ok {
+		t.Error("GeometricMean empty: should return false")
+	}
+	if _, ok := stream.GeometricMean(stream.Of(1.0, 0.0, 2.0)); ok {
+		t.Error("GeometricMean with zero: should return false")
+	}
+	if _, ok := stream.GeometricMean(stream.Of(1, -2)); ok {
+		t.Error("GeometricMean with negative: should return false")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	st, ok := stream.Summarize(stream.Of(3, 1, 4, 1, 5, 9, 2, 6))
+	if !ok {
+		t.Fatal("Summarize: expected ok")
+	}
+	expected := stream.Stats[int]{Count: 8, Sum: 31, Min: 1, Max: 9, Mean: 3.875}
+	if st != expected {
+		t.Errorf("Summarize: expected %+v, got %+v", expected, st)
 	}
 }
 
-func TestMapIndexed(t *testing.T) {
-	result := stream.MapIndexed(
-		stream.Of("a", "b", "c"),
-		func(i int, s string) string { return fmt.Sprintf("%d:%s", i, s) },
-	).ToSlice()
+func TestSummarizeBy(t *testing.T) {
+	st, ok := stream.SummarizeBy(stream.Of(
+		Product{Price: 1200},
+		Product{Price: 25},
+		Product{Price: 75},
+	), func(p Product) float64 { return p.Price })
+	if !ok || st.Count != 3 || st.Sum != 1300 || st.Min != 25 || st.Max != 1200 {
+		t.Errorf("SummarizeBy: unexpected %+v", st)
+	}
+	if math.Abs(st.Mean-1300.0/3.0) > 1e-9 {
+		t.Errorf("SummarizeBy: expected mean %f, got %f", 1300.0/3.0, st.Mean)
+	}
+}
 
-	if result[0] != "0:a" || result[2] != "2:c" {
-		t.Errorf("MapIndexed: unexpected %v", result)
+func TestSummarize_Empty(t *testing.T) {
+	st, ok := stream.Summarize(stream.Of[int]())
+	if ok || st != (stream.Stats[int]{}) {
+		t.Errorf("Summarize empty: expected zero Stats and false, got %+v, %v", st, ok)
 	}
 }
 
-func TestFlatMap(t *testing.T) {
-	users := stream.Of(
-		User{Name: "Alice", Orders: []Order{{Product: "A"}, {Product: "B"}}},
-		User{Name: "Bob", Orders: []Order{{Product: "C"}}},
-	)
+func TestMinMaxSinglePass(t *testing.T) {
+	pulls := 0
+	s := stream.Of(3, 1, 4, 1, 5, 9, 2).Peek(func(int) { pulls++ })
+	lo, hi, ok := stream.MinMax(s)
+	if !ok || lo != 1 || hi != 9 || pulls != 7 {
+		t.Errorf("MinMax: expected 1, 9 in 7 pulls, got %d, %d, %v in %d", lo, hi, ok, pulls)
+	}
+	if _, _, ok := stream.MinMax(stream.Of[float64]()); ok {
+		t.Error("MinMax empty: expected false")
+	}
+}
 
-	orders := stream.FlatMap(users, func(u User) []Order { return u.Orders })
-	if orders.Count() != 3 {
-		t.Errorf("FlatMap: expected 3 orders, got %d", orders.Count())
+func TestMinMaxBy(t *testing.T) {
+	lo, hi, ok := stream.MinMaxBy(stream.Of("pear", "fig", "banana", "kiwi"), func(a, b string) bool { return len(a) < len(b) })
+	if !ok || lo != "fig" || hi != "banana" {
+		t.Errorf("MinMaxBy: expected fig, banana, got %q, %q, %v", lo, hi, ok)
 	}
 }
 
-func TestGroupBy(t *testing.T) {
-	products := stream.Of(
-		Product{Name: "Laptop", Category: "Electronics", Price: 1200},
-		Product{Name: "T-Shirt", Category: "Clothing", Price: 25},
-		Product{Name: "Keyboard", Category: "Electronics", Price: 75},
-	)
+func TestProduct(t *testing.T) {
+	if p := stream.Product(stream.Of(2, 3, 4)); p != 24 {
+		t.Errorf("Product: expected 24, got %d", p)
+	}
+	if p := stream.Product(stream.Of[int]()); p != 1 {
+		t.Errorf("Product empty: expected 1, got %d", p)
+	}
+	if p := stream.Product(stream.Of[uint8](16, 17)); p != 16 {
+		t.Errorf("Product overflow: expected wrap to 16, got %d", p)
+	}
+}
 
-	groups := stream.GroupBy(products, func(p Product) string { return p.Category })
+func TestProductBy(t *testing.T) {
+	type event struct{ p float64 }
+	p := stream.ProductBy(stream.Of(event{0.5}, event{0.5}, event{0.25}), func(e event) float64 { return e.p })
+	if p != 0.0625 {
+		t.Errorf("ProductBy: expected 0.0625, got %v", p)
+	}
+}
 
-	if len(groups["Electronics"]) != 2 {
-		t.Errorf("GroupBy: expected 2 Electronics products")
+func TestMedian(t *testing.T) {
+	if m, ok := stream.Median(stream.Of(5, 1, 3)); !ok || m != 3 {
+		t.Errorf("Median odd: expected 3, got %v, %v", m, ok)
 	}
-	if len(groups["Clothing"]) != 1 {
-		t.Errorf("GroupBy: expected 1 Clothing product")
+	if m, ok := stream.Median(stream.Of(4, 1, 3, 2)); !ok || m != 2.5 {
+		t.Errorf("Median even: expected 2.5, got %v, %v", m, ok)
+	}
+	if _, ok := stream.Median(stream.Of[int]()); ok {
+		t.Error("Median empty: expected false")
 	}
 }
 
-func TestZip(t *testing.T) {
-	names := stream.Of("Alice", "Bob", "Charlie")
-	scores := stream.Of(85.0, 92.0, 78.0)
+func TestVariance(t *testing.T) {
+	s := stream.Of(2, 4, 4, 4, 5, 5, 7, 9)
+	if v := stream.Variance(s); math.Abs(v-4) > 1e-9 {
+		t.Errorf("Variance: expected 4, got %v", v)
+	}
+	if v := stream.SampleVariance(s); math.Abs(v-32.0/7) > 1e-9 {
+		t.Errorf("SampleVariance: expected %v, got %v", 32.0/7, v)
+	}
+	if sd := stream.StdDev(s); math.Abs(sd-2) > 1e-9 {
+		t.Errorf("StdDev: expected 2, got %v", sd)
+	}
+}
 
-	pairs := stream.Zip(names, scores).ToSlice()
-	if len(pairs) != 3 || pairs[0].First != "Alice" || pairs[0].Second != 85.0 {
-		t.Errorf("Zip: unexpected %v", pairs)
+func TestVariance_Small(t *testing.T) {
+	if v := stream.Variance(stream.Of[float64]()); v != 0 {
+		t.Errorf("Variance empty: expected 0, got %v", v)
+	}
+	if v := stream.SampleVariance(stream.Of(3.0)); v != 0 {
+		t.Errorf("SampleVariance single: expected 0, got %v", v)
+	}
+	if sd := stream.StdDev(stream.Of[int]()); sd != 0 {
+		t.Errorf("StdDev empty: expected 0, got %v", sd)
 	}
 }
 
-func TestZipUnevenLengths(t *testing.T) {
-	s1 := stream.Of(1, 2, 3, 4, 5)
-	s2 := stream.Of("a", "b")
+func TestResample(t *testing.T) {
+	up := stream.Resample(stream.Of(0, 10, 20), 5).ToSlice()
+	if !slices.Equal(up, []float64{0, 5, 10, 15, 20}) {
+		t.Errorf("Resample up: expected [0 5 10 15 20], got %v", up)
+	}
 
-	pairs := stream.Zip(s1, s2).ToSlice()
-	if len(pairs) != 2 {
-		t.Errorf("Zip uneven: expected 2, got %d", len(pairs))
+	down := stream.Resample(stream.Of(0.0, 1.0, 4.0, 9.0, 16.0), 3).ToSlice()
+	if !slices.Equal(down, []float64{0, 4, 16}) {
+		t.Errorf("Resample down: expected [0 4 16], got %v", down)
+	}
+
+	uneven := stream.Resample(stream.Of(0, 30), 4).ToSlice()
+	if !slices.Equal(uneven, []float64{0, 10, 20, 30}) {
+		t.Errorf("Resample uneven: expected [0 10 20 30], got %v", uneven)
+	}
+
+	for _, tc := range []struct {
+		in     []int
+		target int
+	}{{[]int{0, 7}, 50}, {[]int{0, 7, 14, 21}, 48}} {
+		out := stream.Resample(stream.From(tc.in), tc.target).ToSlice()
+		first, last := float64(tc.in[0]), float64(tc.in[len(tc.in)-1])
+		if len(out) != tc.target || out[0] != first || out[len(out)-1] != last {
+			t.Errorf("Resample(%v, %d): expected endpoints %v and %v exactly, got %v and %v",
+				tc.in, tc.target, first, last, out[0], out[len(out)-1])
+		}
 	}
 }
 
-func TestAssociate(t *testing.T) {
-	users := stream.Of(
-		User{Name: "Alice", Age: 30},
-		User{Name: "Bob", Age: 25},
-	)
+func TestResample_EdgeCases(t *testing.T) {
+	if result := stream.Resample(stream.Of(7), 3).ToSlice(); !slices.Equal(result, []float64{7, 7, 7}) {
+		t.Errorf("Resample single point: expected [7 7 7], got %v", result)
+	}
+	if result := stream.Resample(stream.Of(1, 2, 3), 1).ToSlice(); !slices.Equal(result, []float64{1}) {
+		t.Errorf("Resample(1): expected [1], got %v", result)
+	}
+	if result := stream.Resample(stream.Of(1, 2, 3), 0).ToSlice(); len(result) != 0 {
+		t.Errorf("Resample(0): expected empty, got %v", result)
+	}
+	if result := stream.Resample(stream.Of[int](), 4).ToSlice(); len(result) != 0 {
+		t.Errorf("Resample empty: expected empty, got %v", result)
+	}
+}
 
-	nameToAge := stream.Associate(users, func(u User) (string, int) {
-		return u.Name, u.Age
-	})
+func TestResample_EarlyBreak(t *testing.T) {
+	result := stream.Resample(stream.Of(0, 100), 101).Take(3).ToSlice()
+	if !slices.Equal(result, []float64{0, 1, 2}) {
+		t.Errorf("Resample early break: expected [0 1 2], got %v", result)
+	}
+}
 
-	if nameToAge["Alice"] != 30 || nameToAge["Bob"] != 25 {
-		t.Errorf("Associate: unexpected %v", nameToAge)
+func TestScale(t *testing.T) {
+	fahrenheit := stream.Scale(stream.Of(-40, 0, 37, 100), 1.8, 32).ToSlice()
+	expected := []float64{-40, 32, 98.6, 212}
+	for i, v := range fahrenheit {
+		if math.Abs(v-expected[i]) > 1e-9 {
+			t.Errorf("Scale: expected %f at %d, got %f", expected[i], i, v)
+		}
 	}
 }
 
-func TestReduceTypeChanging(t *testing.T) {
-	total := stream.Reduce(
-		stream.Of(
-			Order{Amount: 100, Discount: 0.1},
-			Order{Amount: 200, Discount: 0.2},
-			Order{Amount: 300, Discount: 0.0},
-		),
-		0.0,
-		func(acc float64, o Order) float64 {
-			return acc + o.Amount*(1-o.Discount)
-		},
-	)
+// ---------------------------------------------------------------------------
+// iter.Seq bridge tests
+// ---------------------------------------------------------------------------
 
-	expected := 90.0 + 160.0 + 300.0
-	if total != expected {
-		t.Errorf("Reduce: expected %.1f, got %.1f", expected, total)
+func TestFlattenSeq(t *testing.T) {
+	result := stream.FlattenSeq(stream.Of(
+		slices.Values([]int{1, 2}),
+		slices.Values([]int{}),
+		slices.Values([]int{3, 4, 5}),
+	)).ToSlice()
+	expected := []int{1, 2, 3, 4, 5}
+	if !slices.Equal(result, expected) {
+		t.Errorf("FlattenSeq: expected %v, got %v", expected, result)
 	}
 }
 
-func TestFlatten(t *testing.T) {
-	result := stream.Flatten(
-		stream.Of([]int{1, 2}, []int{3, 4}, []int{5}),
+func TestFlattenSeq_EarlyBreakMidSeq(t *testing.T) {
+	var thirdIterated bool
+	third := iter.Seq[int](func(yield func(int) bool) {
+		thirdIterated = true
+		yield(6)
+	})
+	result := stream.FlattenSeq(stream.Of(
+		slices.Values([]int{1, 2}),
+		slices.Values([]int{3, 4, 5}),
+		third,
+	)).Take(3).ToSlice()
+	if !slices.Equal(result, []int{1, 2, 3}) {
+		t.Errorf("FlattenSeq early break: expected [1 2 3], got %v", result)
+	}
+	if thirdIterated {
+		t.Error("FlattenSeq early break: third iterator should not be reached")
+	}
+}
+
+func TestCollectDistinct(t *testing.T) {
+	result := stream.CollectDistinct(slices.Values([]string{"b", "a", "b", "c", "a", "d"})).ToSlice()
+	expected := []string{"b", "a", "c", "d"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("CollectDistinct: expected %v, got %v", expected, result)
+	}
+}
+
+func TestCollectDistinctBy(t *testing.T) {
+	result := stream.CollectDistinctBy(
+		slices.Values([]string{"apple", "Avocado", "banana", "blueberry", "cherry"}),
+		func(s string) byte { return strings.ToLower(s)[0] },
 	).ToSlice()
-	if len(result) != 5 || result[0] != 1 || result[4] != 5 {
-		t.Errorf("Flatten: unexpected %v", result)
+	expected := []string{"apple", "banana", "cherry"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("CollectDistinctBy: expected %v, got %v", expected, result)
 	}
 }
 
-func TestToMap(t *testing.T) {
-	m := stream.ToMap(stream.Zip(
-		stream.Of("a", "b", "c"),
-		stream.Of(1, 2, 3),
-	))
-	if m["a"] != 1 || m["c"] != 3 {
-		t.Errorf("ToMap: unexpected %v", m)
+func TestCollectDistinct_EarlyBreak(t *testing.T) {
+	result := stream.CollectDistinct(slices.Values([]int{1, 1, 2, 3})).Take(2).ToSlice()
+	if !slices.Equal(result, []int{1, 2}) {
+		t.Errorf("CollectDistinct early break: expected [1 2], got %v", result)
 	}
 }
 
-func TestEnumerate(t *testing.T) {
-	result := stream.Enumerate(stream.Of("a", "b", "c")).ToSlice()
-	if len(result) != 3 || result[0].First != 0 || result[0].Second != "a" {
-		t.Errorf("Enumerate: unexpected %v", result)
+func TestCollectMapped(t *testing.T) {
+	result := stream.CollectMapped(slices.Values([]int{1, 2, 3}), func(n int) string {
+		return fmt.Sprintf("#%d", n)
+	}).ToSlice()
+	expected := []string{"#1", "#2", "#3"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("CollectMapped: expected %v, got %v", expected, result)
 	}
-	if result[2].First != 2 || result[2].Second != "c" {
-		t.Errorf("Enumerate: unexpected last %v", result[2])
+}
+
+func TestCollectMapped_EarlyBreak(t *testing.T) {
+	v, ok := stream.CollectMapped(slices.Values([]int{1, 2, 3}), func(n int) int { return -n }).First()
+	if !ok || v != -1 {
+		t.Errorf("CollectMapped early break: expected -1, got %d", v)
+	}
+}
+
+func TestCollectN(t *testing.T) {
+	pulled := 0
+	source := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	result := stream.CollectN(source, 5).ToSlice()
+	if !slices.Equal(result, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("CollectN: expected [0 1 2 3 4], got %v", result)
+	}
+	if pulled != 5 {
+		t.Errorf("CollectN: expected 5 elements pulled, got %d", pulled)
+	}
+
+	pulled = 0
+	if result := stream.CollectN(source, 0).ToSlice(); len(result) != 0 || pulled != 0 {
+		t.Errorf("CollectN(0): expected empty without pulling, got %v (pulled %d)", result, pulled)
+	}
+}
+
+func TestReverseSeq(t *testing.T) {
+	result := stream.ReverseSeq(slices.Values([]int{1, 2, 3})).ToSlice()
+	if !slices.Equal(result, []int{3, 2, 1}) {
+		t.Errorf("ReverseSeq: expected [3 2 1], got %v", result)
 	}
 }
 
 // ---------------------------------------------------------------------------
-// Numeric function tests
+// Concurrent operation tests
 // ---------------------------------------------------------------------------
 
-func TestSum(t *testing.T) {
-	total := stream.Sum(stream.Of(1, 2, 3, 4, 5))
-	if total != 15 {
-		t.Errorf("Sum: expected 15, got %d", total)
+func TestParForEachChunk(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		sizes  []int
+		total  int
+		chunks atomic.Int32
+	)
+	err := stream.ParForEachChunk(stream.Range(0, 103), 10, 4, func(batch []int) error {
+		chunks.Add(1)
+		mu.Lock()
+		defer mu.Unlock()
+		sizes = append(sizes, len(batch))
+		for _, v := range batch {
+			total += v
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ParForEachChunk: unexpected error %v", err)
+	}
+	if chunks.Load() != 11 {
+		t.Errorf("ParForEachChunk: expected 11 chunks, got %d", chunks.Load())
+	}
+	if total != 103*102/2 {
+		t.Errorf("ParForEachChunk: expected sum %d, got %d", 103*102/2, total)
+	}
+	slices.Sort(sizes)
+	if sizes[0] != 3 || sizes[len(sizes)-1] != 10 {
+		t.Errorf("ParForEachChunk: unexpected chunk sizes %v", sizes)
 	}
 }
 
-func TestAvg(t *testing.T) {
-	avg := stream.Avg(stream.Of(10.0, 20.0, 30.0))
-	if avg != 20.0 {
-		t.Errorf("Avg: expected 20.0, got %f", avg)
+func TestParForEachChunk_EarlyAbort(t *testing.T) {
+	// Repeat to exercise different scheduling interleavings after the error.
+	for range 20 {
+		var processed atomic.Int32
+		pulled := 0
+		src := stream.Range(0, 10_000).Peek(func(int) { pulled++ })
+		err := stream.ParForEachChunk(src, 10, 2, func(batch []int) error {
+			processed.Add(1)
+			if batch[0] == 0 {
+				return fmt.Errorf("chunk %d failed", batch[0])
+			}
+			return nil
+		})
+		if err == nil || err.Error() != "chunk 0 failed" {
+			t.Fatalf("ParForEachChunk abort: expected first error, got %v", err)
+		}
+		if processed.Load() >= 1000 || pulled >= 10_000 {
+			t.Fatalf("ParForEachChunk abort: processed %d chunks, pulled %d elements", processed.Load(), pulled)
+		}
 	}
 }
 
-func TestSumByAvgBy(t *testing.T) {
-	products := stream.Of(
-		Product{Price: 1200},
-		Product{Price: 150},
-		Product{Price: 75},
-	)
+func TestParForEachChunk_InvalidArgs(t *testing.T) {
+	calls := 0
+	if err := stream.ParForEachChunk(stream.Of(1, 2, 3), 0, 2, func([]int) error {
+		calls++
+		return nil
+	}); err != nil || calls != 0 {
+		t.Errorf("ParForEachChunk(size 0): expected no-op, got err=%v calls=%d", err, calls)
+	}
+	// workers < 1 falls back to a single worker
+	if err := stream.ParForEachChunk(stream.Of(1, 2, 3), 2, 0, func([]int) error {
+		calls++
+		return nil
+	}); err != nil || calls != 2 {
+		t.Errorf("ParForEachChunk(workers 0): expected 2 calls, got err=%v calls=%d", err, calls)
+	}
+}
 
-	total := stream.SumBy(products, func(p Product) float64 { return p.Price })
-	if total != 1425 {
-		t.Errorf("SumBy: expected 1425, got %f", total)
+func TestParMapErr(t *testing.T) {
+	var active, maxActive atomic.Int32
+	result, err := stream.ParMapErr(stream.Range(0, 50), 4, func(n int) (string, error) {
+		cur := active.Add(1)
+		defer active.Add(-1)
+		for {
+			prev := maxActive.Load()
+			if cur <= prev || maxActive.CompareAndSwap(prev, cur) {
+				break
+			}
+		}
+		// Finish later elements first to scramble completion order.
+		time.Sleep(time.Duration(50-n) * 20 * time.Microsecond)
+		return fmt.Sprintf("v%d", n), nil
+	})
+	if err != nil {
+		t.Fatalf("ParMapErr: unexpected error %v", err)
+	}
+	if len(result) != 50 {
+		t.Fatalf("ParMapErr: expected 50 results, got %d", len(result))
+	}
+	for i, v := range result {
+		if v != fmt.Sprintf("v%d", i) {
+			t.Errorf("ParMapErr: expected v%d at %d, got %s", i, i, v)
+		}
+	}
+	if maxActive.Load() < 2 {
+		t.Errorf("ParMapErr: expected concurrent execution, max active %d", maxActive.Load())
 	}
+}
 
-	products2 := stream.Of(
-		Product{Price: 1200},
-		Product{Price: 150},
-		Product{Price: 75},
-	)
-	avg := stream.AvgBy(products2, func(p Product) float64 { return p.Price })
-	expected := 1425.0 / 3.0
-	if avg < expected-0.01 || avg > expected+0.01 {
-		t.Errorf("AvgBy: expected ~%.2f, got %.2f", expected, avg)
+func TestParMapErr_Error(t *testing.T) {
+	for range 20 {
+		var calls atomic.Int32
+		result, err := stream.ParMapErr(stream.Range(0, 10_000), 3, func(n int) (int, error) {
+			calls.Add(1)
+			if n == 5 {
+				return 0, fmt.Errorf("bad element %d", n)
+			}
+			return n, nil
+		})
+		if err == nil || err.Error() != "bad element 5" || result != nil {
+			t.Fatalf("ParMapErr error: expected first error and nil result, got %v, %v", result, err)
+		}
+		if calls.Load() >= 10_000 {
+			t.Fatalf("ParMapErr error: expected early abort, got %d calls", calls.Load())
+		}
 	}
 }
 
-func TestMinMax(t *testing.T) {
-	s := stream.Of(3.0, 1.0, 4.0, 1.0, 5.0)
+func TestParMapErr_Empty(t *testing.T) {
+	result, err := stream.ParMapErr(stream.Of[int](), 0, func(n int) (int, error) { return n, nil })
+	if err != nil || result == nil || len(result) != 0 {
+		t.Errorf("ParMapErr empty: expected non-nil empty slice, got %v, %v", result, err)
+	}
+}
 
-	min, _ := stream.Min(s)
-	if min != 1.0 {
-		t.Errorf("Min: expected 1.0, got %f", min)
+func TestMapParallel(t *testing.T) {
+	var active, maxActive atomic.Int32
+	result := stream.MapParallel(stream.Range(0, 50), 4, func(n int) string {
+		cur := active.Add(1)
+		defer active.Add(-1)
+		for {
+			prev := maxActive.Load()
+			if cur <= prev || maxActive.CompareAndSwap(prev, cur) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(n%5) * 50 * time.Microsecond)
+		return fmt.Sprintf("v%d", n)
+	}).ToSlice()
+	if len(result) != 50 {
+		t.Fatalf("MapParallel: expected 50 results, got %d", len(result))
+	}
+	for i, v := range result {
+		if v != fmt.Sprintf("v%d", i) {
+			t.Errorf("MapParallel: expected v%d at %d, got %s", i, i, v)
+		}
+	}
+	if m := maxActive.Load(); m < 2 || m > 4 {
+		t.Errorf("MapParallel: expected between 2 and 4 concurrent calls, got %d", m)
 	}
+}
 
-	s2 := stream.Of(3.0, 1.0, 4.0, 1.0, 5.0)
-	max, _ := stream.Max(s2)
-	if max != 5.0 {
-		t.Errorf("Max: expected 5.0, got %f", max)
+func TestMapParallel_EarlyBreak(t *testing.T) {
+	var calls atomic.Int32
+	result := stream.MapParallel(stream.Naturals(), 3, func(n int) int {
+		calls.Add(1)
+		return n * 2
+	}).Take(5).ToSlice()
+	if !slices.Equal(result, []int{0, 2, 4, 6, 8}) {
+		t.Errorf("MapParallel early break: expected [0 2 4 6 8], got %v", result)
+	}
+	if n := calls.Load(); n > 20 {
+		t.Errorf("MapParallel early break: expected bounded read-ahead, got %d calls", n)
+	}
+}
+
+func TestMapParallel_Sequential(t *testing.T) {
+	result := stream.MapParallel(stream.Of(1, 2, 3), 1, func(n int) int { return n + 1 }).ToSlice()
+	if !slices.Equal(result, []int{2, 3, 4}) {
+		t.Errorf("MapParallel(workers 1): expected [2 3 4], got %v", result)
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	result := stream.Range(0, 100).Buffer(8).Filter(func(n int) bool { return n%10 == 0 }).ToSlice()
+	if !slices.Equal(result, []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90}) {
+		t.Errorf("Buffer: unexpected %v", result)
+	}
+	if result := stream.Of(1, 2).Buffer(0).ToSlice(); !slices.Equal(result, []int{1, 2}) {
+		t.Errorf("Buffer(0): expected unchanged stream, got %v", result)
+	}
+}
+
+func TestBuffer_EarlyBreakNoLeak(t *testing.T) {
+	var finished atomic.Bool
+	source := stream.Collect(func(yield func(int) bool) {
+		defer finished.Store(true)
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	})
+	result := source.Buffer(4).Take(3).ToSlice()
+	if !slices.Equal(result, []int{0, 1, 2}) {
+		t.Errorf("Buffer early break: expected [0 1 2], got %v", result)
+	}
+	if !finished.Load() {
+		t.Error("Buffer early break: producer goroutine should have exited")
+	}
+}
+
+func TestToChannel(t *testing.T) {
+	var result []int
+	for v := range stream.Range(0, 5).ToChannel(2) {
+		result = append(result, v)
+	}
+	if !slices.Equal(result, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("ToChannel: expected [0 1 2 3 4], got %v", result)
+	}
+	if _, ok := <-stream.Of[int]().ToChannel(0); ok {
+		t.Error("ToChannel empty: expected closed channel")
+	}
+}
+
+func TestToChannelCtx(t *testing.T) {
+	var result []int
+	for v := range stream.Range(0, 5).ToChannelCtx(context.Background(), 2) {
+		result = append(result, v)
+	}
+	if !slices.Equal(result, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("ToChannelCtx: expected [0 1 2 3 4], got %v", result)
+	}
+}
+
+func TestToChannelCtx_Cancel(t *testing.T) {
+	var finished atomic.Bool
+	source := stream.Collect(func(yield func(int) bool) {
+		defer finished.Store(true)
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := source.ToChannelCtx(ctx, -1)
+	for v := range ch {
+		if v == 3 {
+			cancel()
+			break
+		}
+	}
+	// The producer exits and closes the channel after cancellation.
+	for range ch {
+	}
+	if !finished.Load() {
+		t.Error("ToChannelCtx cancel: producer goroutine should have exited")
 	}
 }
 
@@ -885,6 +3576,14 @@ func TestGenerate_EarlyBreak(t *testing.T) {
 	}
 }
 
+func TestGenerate2_EarlyBreak(t *testing.T) {
+	calls := 0
+	result := stream.Generate2(100, func(i int) int { calls++; return i }).Take(2).ToSlice()
+	if len(result) != 2 || calls != 2 {
+		t.Errorf("Generate2 early break: got %v after %d calls", result, calls)
+	}
+}
+
 func TestRepeatN_EarlyBreak(t *testing.T) {
 	result := stream.RepeatN("x", 100).Take(1).ToSlice()
 	if len(result) != 1 || result[0] != "x" {
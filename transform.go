@@ -1,6 +1,13 @@
 package stream
 
-import "iter"
+import (
+	"cmp"
+	"iter"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+)
 
 // ---------------------------------------------------------------------------
 // Top-level functions (type-changing operations: T → U)
@@ -39,6 +46,67 @@ func MapIndexed[T, U any](s Stream[T], fn func(int, T) U) Stream[U] {
 	}}
 }
 
+// FilterMap lazily transforms each element, yielding only results for which
+// fn returns true. It replaces a Map followed by a Filter in a single pass.
+//
+//	ids := stream.FilterMap(stream.Of(raw...), func(s string) (int, bool) {
+//	    n, err := strconv.Atoi(s)
+//	    return n, err == nil
+//	})
+func FilterMap[T, U any](s Stream[T], fn func(T) (U, bool)) Stream[U] {
+	seq := s.seq
+	return Stream[U]{seq: func(yield func(U) bool) {
+		for v := range seq {
+			if u, ok := fn(v); ok {
+				if !yield(u) {
+					return
+				}
+			}
+		}
+	}}
+}
+
+// MapWhile lazily transforms elements until fn returns false, then stops
+// iterating the source entirely. It is the transforming analog of
+// TakeWhile; unlike FilterMap, the first rejected element ends the Stream.
+//
+//	header := stream.MapWhile(lines, func(l string) (string, bool) {
+//	    return strings.TrimPrefix(l, "# "), strings.HasPrefix(l, "# ")
+//	})
+func MapWhile[T, U any](s Stream[T], fn func(T) (U, bool)) Stream[U] {
+	seq := s.seq
+	return Stream[U]{seq: func(yield func(U) bool) {
+		for v := range seq {
+			u, ok := fn(v)
+			if !ok || !yield(u) {
+				return
+			}
+		}
+	}}
+}
+
+// FilterMapIndexed lazily transforms each element together with its index,
+// yielding only results for which fn returns true. The index is the
+// element's position in the source Stream.
+//
+//	evensDoubled := stream.FilterMapIndexed(s, func(i, n int) (int, bool) {
+//	    return n * 2, i%2 == 0
+//	})
+func FilterMapIndexed[T, U any](s Stream[T], fn func(int, T) (U, bool)) Stream[U] {
+	seq := s.seq
+	return Stream[U]{seq: func(yield func(U) bool) {
+		i := 0
+		for v := range seq {
+			if u, ok := fn(i, v); ok {
+				if !yield(u) {
+					return
+				}
+			}
+			i++
+		}
+	}}
+}
+
 // FlatMap lazily transforms each element into a slice and flattens the result.
 //
 //	allOrders := stream.FlatMap(
@@ -58,6 +126,16 @@ func FlatMap[T, U any](s Stream[T], fn func(T) []U) Stream[U] {
 	}}
 }
 
+// FlatMapIndexed is like FlatMap but also passes each source element's
+// index. The index counts source elements, not emitted ones.
+//
+//	tagged := stream.FlatMapIndexed(stream.Of(orders...), func(i int, o Order) []Line {
+//	    return o.LinesWithOrdinal(i)
+//	})
+func FlatMapIndexed[T, U any](s Stream[T], fn func(int, T) []U) Stream[U] {
+	return Flatten(MapIndexed(s, fn))
+}
+
 // Reduce folds all elements into a value of a different type.
 //
 //	total := stream.Reduce(orders, 0.0, func(acc float64, o Order) float64 {
@@ -71,6 +149,79 @@ func Reduce[T, U any](s Stream[T], initial U, fn func(acc U, item T) U) U {
 	return result
 }
 
+// Scan lazily yields the initial value followed by each successive
+// accumulation into a value of a different type. An empty Stream yields only
+// the initial value.
+//
+//	balances := stream.Scan(transactions, 100.0, func(bal float64, tx Tx) float64 {
+//	    return bal + tx.Amount
+//	})
+func Scan[T, U any](s Stream[T], initial U, fn func(U, T) U) Stream[U] {
+	seq := s.seq
+	return Stream[U]{seq: func(yield func(U) bool) {
+		acc := initial
+		if !yield(acc) {
+			return
+		}
+		for v := range seq {
+			acc = fn(acc, v)
+			if !yield(acc) {
+				return
+			}
+		}
+	}}
+}
+
+// ReduceUntil folds elements into a value of a different type until fn
+// reports that the accumulator is done. Iteration stops as soon as fn
+// returns true, and the second result reports whether that happened before
+// the Stream was exhausted.
+//
+//	batch, full := stream.ReduceUntil(items, []Item{}, func(acc []Item, it Item) ([]Item, bool) {
+//	    acc = append(acc, it)
+//	    return acc, len(acc) == 100
+//	})
+func ReduceUntil[T, U any](s Stream[T], initial U, fn func(acc U, item T) (U, bool)) (U, bool) {
+	result := initial
+	for v := range s.seq {
+		var done bool
+		if result, done = fn(result, v); done {
+			return result, true
+		}
+	}
+	return result, false
+}
+
+// ToSortedSliceBy collects all elements into a slice sorted ascending by the
+// key extracted from each element.
+//
+//	byPrice := stream.ToSortedSliceBy(products, func(p Product) float64 { return p.Price })
+func ToSortedSliceBy[T any, K cmp.Ordered](s Stream[T], key func(T) K) []T {
+	result := s.ToSlice()
+	slices.SortFunc(result, func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	})
+	return result
+}
+
+// JoinString concatenates the elements of a string Stream with sep between
+// them, in a single pass without collecting an intermediate slice. Returns
+// "" for an empty Stream.
+//
+//	csv := stream.JoinString(stream.Map(fields, strings.TrimSpace), ",")
+func JoinString(s Stream[string], sep string) string {
+	var b strings.Builder
+	first := true
+	for v := range s.seq {
+		if !first {
+			b.WriteString(sep)
+		}
+		b.WriteString(v)
+		first = false
+	}
+	return b.String()
+}
+
 // GroupBy groups elements by a key function and returns a map of key → slice.
 //
 //	bySymbol := stream.GroupBy(trades, func(t Trade) string { return t.Symbol })
@@ -84,6 +235,73 @@ func GroupBy[T any, K comparable](s Stream[T], key func(T) K) map[K][]T {
 	return groups
 }
 
+// Tally counts the occurrences of each distinct element.
+// Returns an empty, non-nil map for an empty Stream.
+//
+//	stream.Tally(stream.Of("a", "b", "a")) // map[a:2 b:1]
+func Tally[T comparable](s Stream[T]) map[T]int {
+	return TallyBy(s, func(v T) T { return v })
+}
+
+// TallyBy counts the elements sharing each key derived by key.
+// Returns an empty, non-nil map for an empty Stream.
+//
+//	byLevel := stream.TallyBy(logs, func(l Log) string { return l.Level })
+func TallyBy[T any, K comparable](s Stream[T], key func(T) K) map[K]int {
+	counts := make(map[K]int)
+	for v := range s.seq {
+		counts[key(v)]++
+	}
+	return counts
+}
+
+// Mode returns the most frequent element, or false if the Stream is empty.
+// On a tie, the element that reached the highest count first wins: for
+// a, b, b, a the result is b.
+func Mode[T comparable](s Stream[T]) (T, bool) {
+	counts := make(map[T]int)
+	var best T
+	bestCount := 0
+	for v := range s.seq {
+		counts[v]++
+		if c := counts[v]; c > bestCount {
+			best, bestCount = v, c
+		}
+	}
+	return best, bestCount > 0
+}
+
+// GroupBySortedValues groups elements by key and sorts each group by cmp.
+//
+//	byCategory := stream.GroupBySortedValues(products,
+//	    func(p Product) string { return p.Category },
+//	    func(a, b Product) int { return cmp.Compare(a.Price, b.Price) },
+//	)
+func GroupBySortedValues[T any, K comparable](s Stream[T], key func(T) K, cmp func(a, b T) int) map[K][]T {
+	groups := GroupBy(s, key)
+	for _, group := range groups {
+		slices.SortFunc(group, cmp)
+	}
+	return groups
+}
+
+// GroupByThen groups elements by key and applies transform to each group,
+// returning a map of key → transformed group. Returns an empty map for an
+// empty Stream.
+//
+//	totals := stream.GroupByThen(orders,
+//	    func(o Order) int { return o.UserID },
+//	    func(g []Order) float64 { return stream.SumBy(stream.Of(g...), Order.Total) },
+//	)
+func GroupByThen[T any, K comparable, V any](s Stream[T], key func(T) K, transform func([]T) V) map[K]V {
+	groups := GroupBy(s, key)
+	result := make(map[K]V, len(groups))
+	for k, group := range groups {
+		result[k] = transform(group)
+	}
+	return result
+}
+
 // Associate creates a map from Stream elements using a key-value function.
 //
 //	userMap := stream.Associate(users, func(u User) (int, string) {
@@ -98,6 +316,22 @@ func Associate[T any, K comparable, V any](s Stream[T], fn func(T) (K, V)) map[K
 	return result
 }
 
+// AssociateBy creates a map from each element's derived key to the element.
+// On duplicate keys, the last element wins.
+//
+//	byID := stream.AssociateBy(users, func(u User) int { return u.ID })
+func AssociateBy[T any, K comparable](s Stream[T], key func(T) K) map[K]T {
+	return Associate(s, func(v T) (K, T) { return key(v), v })
+}
+
+// AssociateWith creates a map from each element to a value derived from it.
+// On duplicate elements, the last derived value wins.
+//
+//	lengths := stream.AssociateWith(words, func(w string) int { return len(w) })
+func AssociateWith[K comparable, V any](s Stream[K], value func(K) V) map[K]V {
+	return Associate(s, func(k K) (K, V) { return k, value(k) })
+}
+
 // Zip lazily combines two Streams into a Stream of pairs.
 // Stops when either Stream is exhausted.
 //
@@ -119,12 +353,96 @@ func Zip[T, U any](s1 Stream[T], s2 Stream[U]) Stream[Pair[T, U]] {
 	}}
 }
 
+// ZipLongest lazily pairs elements from two Streams until both are
+// exhausted, substituting fillA or fillB for the side that ended first. The
+// result is as long as the longer input, so do not use it with two infinite
+// Streams unless a downstream operation stops early.
+//
+//	stream.ZipLongest(stream.Of(1, 2, 3), stream.Of("a"), 0, "-")
+//	// yields {1, "a"}, {2, "-"}, {3, "-"}
+func ZipLongest[A, B any](a Stream[A], b Stream[B], fillA A, fillB B) Stream[Pair[A, B]] {
+	seqA, seqB := a.seq, b.seq
+	return Stream[Pair[A, B]]{seq: func(yield func(Pair[A, B]) bool) {
+		next, stop := iter.Pull(seqB)
+		defer stop()
+		for va := range seqA {
+			vb, ok := next()
+			if !ok {
+				vb = fillB
+			}
+			if !yield(Pair[A, B]{First: va, Second: vb}) {
+				return
+			}
+		}
+		for vb, ok := next(); ok; vb, ok = next() {
+			if !yield(Pair[A, B]{First: fillA, Second: vb}) {
+				return
+			}
+		}
+	}}
+}
+
+// Unzip splits a Stream of Pairs into its First and Second components, the
+// inverse of Zip. It is an alias of UnzipToSlices; empty input yields two
+// empty slices.
+//
+//	keys, values := stream.Unzip(stream.Collect2(maps.All(m)))
+func Unzip[A, B any](s Stream[Pair[A, B]]) ([]A, []B) {
+	return UnzipToSlices(s)
+}
+
+// UnzipToSlices consumes a Stream of Pairs once and returns the First and
+// Second components as two positionally aligned slices.
+//
+//	names, scores := stream.UnzipToSlices(stream.Zip(names, scores))
+func UnzipToSlices[T, U any](s Stream[Pair[T, U]]) ([]T, []U) {
+	firsts, seconds := []T{}, []U{}
+	for p := range s.seq {
+		firsts = append(firsts, p.First)
+		seconds = append(seconds, p.Second)
+	}
+	return firsts, seconds
+}
+
 // Pair holds two values of potentially different types.
 type Pair[T, U any] struct {
 	First  T
 	Second U
 }
 
+// Result holds the outcome of a fallible operation: a value, or the error
+// that prevented producing one.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// TryMap lazily applies a fallible fn to each element, wrapping each outcome
+// in a Result. Errors do not stop the Stream; pair it with CollectResults to
+// stop at the first failure.
+//
+//	nums, err := stream.CollectResults(stream.TryMap(lines, strconv.Atoi))
+func TryMap[T, U any](s Stream[T], fn func(T) (U, error)) Stream[Result[U]] {
+	return Map(s, func(v T) Result[U] {
+		u, err := fn(v)
+		return Result[U]{Value: u, Err: err}
+	})
+}
+
+// CollectResults collects the values of a Stream of Results, stopping at the
+// first Result with a non-nil Err. On error it returns a nil slice and that
+// error; no further elements are pulled from the Stream.
+func CollectResults[T any](s Stream[Result[T]]) ([]T, error) {
+	values := []T{}
+	for r := range s.seq {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		values = append(values, r.Value)
+	}
+	return values, nil
+}
+
 // Flatten lazily flattens a Stream of slices into a flat Stream.
 //
 //	flat := stream.Flatten(stream.Of([]int{1, 2}, []int{3, 4}))
@@ -142,15 +460,51 @@ func Flatten[T any](s Stream[[]T]) Stream[T] {
 	}}
 }
 
-// ToMap collects a Stream of Pairs into a map.
+// ConcatStreams lazily concatenates a Stream of Streams in order, emitting
+// elements as each inner Stream produces them. Inner Streams are not
+// iterated until the previous one is exhausted, and an early break stops
+// both the current inner Stream and the outer one.
+//
+//	all := stream.ConcatStreams(stream.Map(stream.Of(pages...), fetchPage))
+func ConcatStreams[T any](outer Stream[Stream[T]]) Stream[T] {
+	return FlattenSeq(Map(outer, Stream[T].Seq))
+}
+
+// ToMap collects a Stream of Pairs into a map. On duplicate keys, the last
+// value wins; use ToMapWith to resolve them differently.
 func ToMap[K comparable, V any](s Stream[Pair[K, V]]) map[K]V {
+	return ToMapWith(s, func(_, incoming V) V { return incoming })
+}
+
+// ToMapWith collects a Stream of Pairs into a map, calling merge to combine
+// the existing and incoming values whenever a key repeats.
+//
+//	totals := stream.ToMapWith(stream.Zip(names, amounts), func(a, b int) int { return a + b })
+func ToMapWith[K comparable, V any](s Stream[Pair[K, V]], merge func(existing, incoming V) V) map[K]V {
 	result := make(map[K]V)
 	for pair := range s.seq {
-		result[pair.First] = pair.Second
+		if existing, ok := result[pair.First]; ok {
+			result[pair.First] = merge(existing, pair.Second)
+		} else {
+			result[pair.First] = pair.Second
+		}
 	}
 	return result
 }
 
+// ToSet collects the distinct elements of a Stream into a set for fast
+// membership checks. Returns an empty, non-nil map for an empty Stream.
+//
+//	allowed := stream.ToSet(stream.Of(ids...))
+//	if _, ok := allowed[id]; ok { ... }
+func ToSet[T comparable](s Stream[T]) map[T]struct{} {
+	set := make(map[T]struct{})
+	for v := range s.seq {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
 // Enumerate wraps each element with its index as a Pair[int, T].
 //
 //	stream.Enumerate(stream.Of("a", "b", "c"))
@@ -167,3 +521,795 @@ func Enumerate[T any](s Stream[T]) Stream[Pair[int, T]] {
 		}
 	}}
 }
+
+// ScanPairs lazily folds the Stream and yields each element paired with the
+// accumulator value after folding it in.
+//
+//	stream.ScanPairs(stream.Of(10, 20, 30), 0, func(acc, n int) int { return acc + n })
+//	// yields {10, 10}, {20, 30}, {30, 60}
+func ScanPairs[T, U any](s Stream[T], initial U, fn func(U, T) U) Stream[Pair[T, U]] {
+	seq := s.seq
+	return Stream[Pair[T, U]]{seq: func(yield func(Pair[T, U]) bool) {
+		acc := initial
+		for v := range seq {
+			acc = fn(acc, v)
+			if !yield(Pair[T, U]{First: v, Second: acc}) {
+				return
+			}
+		}
+	}}
+}
+
+// GroupReduceStream groups elements by key and folds each group into a U,
+// yielding one Pair per key in first-seen key order. Input does not need to
+// be sorted by key.
+// Note: This operation consumes all elements into memory (one accumulator per key).
+//
+//	totals := stream.GroupReduceStream(orders,
+//	    func(o Order) int { return o.UserID },
+//	    0.0,
+//	    func(acc float64, o Order) float64 { return acc + o.Amount },
+//	)
+func GroupReduceStream[T any, K comparable, U any](s Stream[T], key func(T) K, initial U, fn func(U, T) U) Stream[Pair[K, U]] {
+	seq := s.seq
+	return Stream[Pair[K, U]]{seq: func(yield func(Pair[K, U]) bool) {
+		var order []K
+		accs := make(map[K]U)
+		for v := range seq {
+			k := key(v)
+			acc, ok := accs[k]
+			if !ok {
+				acc = initial
+				order = append(order, k)
+			}
+			accs[k] = fn(acc, v)
+		}
+		for _, k := range order {
+			if !yield(Pair[K, U]{First: k, Second: accs[k]}) {
+				return
+			}
+		}
+	}}
+}
+
+// MaxN returns up to n largest elements according to less, ordered from
+// largest to smallest. It makes a single pass and keeps at most n elements
+// in memory using a bounded heap. When n >= the Stream length, all elements
+// are returned sorted in descending order.
+//
+//	top3 := stream.MaxN(scores, 3, func(a, b int) bool { return a < b })
+func MaxN[T any](s Stream[T], n int, less func(a, b T) bool) []T {
+	return boundedTop(s, n, less)
+}
+
+// MinN returns up to n smallest elements according to less, ordered from
+// smallest to largest. It makes a single pass and keeps at most n elements
+// in memory using a bounded heap. When n >= the Stream length, all elements
+// are returned sorted in ascending order.
+func MinN[T any](s Stream[T], n int, less func(a, b T) bool) []T {
+	return boundedTop(s, n, func(a, b T) bool { return less(b, a) })
+}
+
+// boundedTop keeps the n greatest elements (by less) in a min-heap and
+// returns them ordered greatest first.
+func boundedTop[T any](s Stream[T], n int, less func(a, b T) bool) []T {
+	if n <= 0 {
+		return []T{}
+	}
+	heap := []T{}
+	for v := range s.seq {
+		if len(heap) < n {
+			heap = append(heap, v)
+			heapUp(heap, len(heap)-1, less)
+			continue
+		}
+		if less(heap[0], v) {
+			heap[0] = v
+			heapDown(heap, 0, less)
+		}
+	}
+	slices.SortFunc(heap, func(a, b T) int {
+		if less(b, a) {
+			return -1
+		}
+		if less(a, b) {
+			return 1
+		}
+		return 0
+	})
+	return heap
+}
+
+func heapUp[T any](h []T, i int, less func(a, b T) bool) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !less(h[i], h[parent]) {
+			return
+		}
+		h[i], h[parent] = h[parent], h[i]
+		i = parent
+	}
+}
+
+func heapDown[T any](h []T, i int, less func(a, b T) bool) {
+	for {
+		smallest := i
+		for _, child := range [2]int{2*i + 1, 2*i + 2} {
+			if child < len(h) && less(h[child], h[smallest]) {
+				smallest = child
+			}
+		}
+		if smallest == i {
+			return
+		}
+		h[i], h[smallest] = h[smallest], h[i]
+		i = smallest
+	}
+}
+
+// WeightedSample selects up to n elements in a single pass, each with
+// probability proportional to its weight (A-Res weighted reservoir sampling).
+// Elements with a non-positive weight are never selected. Keeps at most n
+// elements in memory regardless of the Stream length.
+//
+//	sample := stream.WeightedSample(users, 100, func(u User) float64 { return u.Score })
+func WeightedSample[T any](s Stream[T], n int, weight func(T) float64) []T {
+	return weightedSample(s, n, weight, rand.Float64)
+}
+
+// WeightedSampleWithRand is like WeightedSample but draws randomness from r,
+// making the selection deterministic for a given seed.
+func WeightedSampleWithRand[T any](s Stream[T], n int, weight func(T) float64, r *rand.Rand) []T {
+	return weightedSample(s, n, weight, r.Float64)
+}
+
+func weightedSample[T any](s Stream[T], n int, weight func(T) float64, random func() float64) []T {
+	// Each element gets the key u^(1/w); the n largest keys form the sample.
+	// Non-positive weights are marked with a negative key and dropped.
+	keyed := Map(s, func(v T) Pair[float64, T] {
+		w := weight(v)
+		if w <= 0 {
+			return Pair[float64, T]{First: -1, Second: v}
+		}
+		return Pair[float64, T]{First: math.Pow(random(), 1/w), Second: v}
+	}).Filter(func(p Pair[float64, T]) bool { return p.First >= 0 })
+	top := boundedTop(keyed, n, func(a, b Pair[float64, T]) bool { return a.First < b.First })
+	result := make([]T, len(top))
+	for i, p := range top {
+		result[i] = p.Second
+	}
+	return result
+}
+
+// Split lazily breaks the Stream into segments at each element satisfying
+// isDelimiter, dropping the delimiters themselves. Like strings.FieldsFunc,
+// empty segments (from leading, trailing, or consecutive delimiters) are
+// skipped. Each segment is emitted as soon as its closing delimiter is seen.
+//
+//	lines := stream.Split(tokens, func(t string) bool { return t == "\n" })
+func Split[T any](s Stream[T], isDelimiter func(T) bool) Stream[[]T] {
+	return split(s, isDelimiter, false)
+}
+
+// SplitKeepEmpty is like Split but emits an empty segment for every
+// delimiter that closes no elements (e.g. consecutive delimiters). Every
+// delimiter terminates a segment; a trailing segment is emitted only if it
+// is non-empty.
+func SplitKeepEmpty[T any](s Stream[T], isDelimiter func(T) bool) Stream[[]T] {
+	return split(s, isDelimiter, true)
+}
+
+func split[T any](s Stream[T], isDelimiter func(T) bool, keepEmpty bool) Stream[[]T] {
+	seq := s.seq
+	return Stream[[]T]{seq: func(yield func([]T) bool) {
+		segment := []T{}
+		for v := range seq {
+			if !isDelimiter(v) {
+				segment = append(segment, v)
+				continue
+			}
+			if len(segment) > 0 || keepEmpty {
+				if !yield(segment) {
+					return
+				}
+			}
+			segment = []T{}
+		}
+		if len(segment) > 0 {
+			yield(segment)
+		}
+	}}
+}
+
+// Batch lazily groups elements into slices of size, emitting each as soon as
+// it fills and the final partial batch at the end. Unlike Chunk, only the
+// current batch is buffered, so it works on infinite Streams. Returns an
+// empty Stream for a non-positive size.
+//
+//	stream.Batch(stream.Naturals(), 100).Take(3) // [0..99], [100..199], [200..299]
+func Batch[T any](s Stream[T], size int) Stream[[]T] {
+	if size <= 0 {
+		return Stream[[]T]{seq: func(yield func([]T) bool) {}}
+	}
+	seq := s.seq
+	return Stream[[]T]{seq: func(yield func([]T) bool) {
+		batch := make([]T, 0, size)
+		for v := range seq {
+			batch = append(batch, v)
+			if len(batch) == size {
+				if !yield(batch) {
+					return
+				}
+				batch = make([]T, 0, size)
+			}
+		}
+		if len(batch) > 0 {
+			yield(batch)
+		}
+	}}
+}
+
+// Window returns a Stream of sliding windows: slices of size consecutive
+// elements, advancing by step elements each time. A trailing window with
+// fewer than size elements is dropped. Only one window is buffered at a
+// time, so it works on infinite Streams. Returns an empty Stream for
+// non-positive size or step.
+//
+//	stream.Window(stream.Range(1, 6), 3, 1) // [1 2 3], [2 3 4], [3 4 5]
+func Window[T any](s Stream[T], size, step int) Stream[[]T] {
+	if size <= 0 || step <= 0 {
+		return Stream[[]T]{seq: func(yield func([]T) bool) {}}
+	}
+	seq := s.seq
+	return Stream[[]T]{seq: func(yield func([]T) bool) {
+		buf := make([]T, 0, size)
+		skip := 0
+		for v := range seq {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			buf = append(buf, v)
+			if len(buf) < size {
+				continue
+			}
+			if !yield(slices.Clone(buf)) {
+				return
+			}
+			if step >= size {
+				skip = step - size
+				buf = buf[:0]
+			} else {
+				buf = append(buf[:0], buf[step:]...)
+			}
+		}
+	}}
+}
+
+// MapMemo is like Map but caches fn's result for each distinct input, so
+// repeated inputs skip recomputation. fn should be pure.
+// Note: The cache lives for one iteration and grows with the number of
+// distinct inputs; prefer it for low-cardinality data.
+//
+//	regions := stream.MapMemo(countryCodes, lookupRegion)
+func MapMemo[T comparable, U any](s Stream[T], fn func(T) U) Stream[U] {
+	seq := s.seq
+	return Stream[U]{seq: func(yield func(U) bool) {
+		cache := make(map[T]U)
+		for v := range seq {
+			u, ok := cache[v]
+			if !ok {
+				u = fn(v)
+				cache[v] = u
+			}
+			if !yield(u) {
+				return
+			}
+		}
+	}}
+}
+
+// HeavyHitters approximates the most frequent elements in a single pass
+// using the Misra-Gries algorithm with at most k counters, so memory stays
+// O(k) regardless of the Stream's cardinality.
+//
+// Guarantees, for a Stream of n elements: every element occurring more than
+// n/(k+1) times is included, and each reported count underestimates the true
+// frequency by at most n/(k+1). Elements near the threshold may be missing
+// or under-counted. Results are ordered by reported count, highest first;
+// equal counts are ordered by when their counter was created (first-seen).
+//
+//	top := stream.HeavyHitters(stream.Of(requestPaths...), 10)
+func HeavyHitters[T comparable](s Stream[T], k int) []Pair[T, int] {
+	if k <= 0 {
+		return []Pair[T, int]{}
+	}
+	type counter struct{ n, created int }
+	counters := make(map[T]*counter, k)
+	created := 0
+	for v := range s.seq {
+		if c, ok := counters[v]; ok {
+			c.n++
+			continue
+		}
+		if len(counters) < k {
+			counters[v] = &counter{n: 1, created: created}
+			created++
+			continue
+		}
+		for key, c := range counters {
+			c.n--
+			if c.n == 0 {
+				delete(counters, key)
+			}
+		}
+	}
+	type entry struct {
+		Pair[T, int]
+		created int
+	}
+	entries := make([]entry, 0, len(counters))
+	for key, c := range counters {
+		entries = append(entries, entry{Pair[T, int]{First: key, Second: c.n}, c.created})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		if c := cmp.Compare(b.Second, a.Second); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.created, b.created)
+	})
+	result := make([]Pair[T, int], len(entries))
+	for i, e := range entries {
+		result[i] = e.Pair
+	}
+	return result
+}
+
+// DistinctWithIndex lazily yields the first occurrence of each key, paired
+// with its index in the source Stream.
+// Note: Maintains a set of seen keys in memory.
+//
+//	stream.DistinctWithIndex(stream.Of("a", "b", "a", "c"), func(s string) string { return s })
+//	// yields {0, "a"}, {1, "b"}, {3, "c"}
+func DistinctWithIndex[T any, K comparable](s Stream[T], key func(T) K) Stream[Pair[int, T]] {
+	seq := s.seq
+	return Stream[Pair[int, T]]{seq: func(yield func(Pair[int, T]) bool) {
+		seen := make(map[K]struct{})
+		i := 0
+		for v := range seq {
+			k := key(v)
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				if !yield(Pair[int, T]{First: i, Second: v}) {
+					return
+				}
+			}
+			i++
+		}
+	}}
+}
+
+// Diff compares two Streams as sets and returns the distinct elements found
+// only in a and only in b, each in first-occurrence order. Useful for
+// reconciling two datasets.
+// Note: a is buffered into memory (one element per distinct key); b is streamed.
+//
+//	missing, extra := stream.Diff(expected, actual)
+func Diff[T comparable](a, b Stream[T]) (onlyA, onlyB []T) {
+	return DiffBy(a, b, func(v T) T { return v })
+}
+
+// Intersect lazily yields the distinct elements of a that also appear in b,
+// in first-occurrence order.
+// Note: b is fully consumed into a set when iteration starts; a is streamed.
+func Intersect[T comparable](a, b Stream[T]) Stream[T] {
+	return filterBySet(a, b, true)
+}
+
+// Difference lazily yields the distinct elements of a that do not appear in
+// b, in first-occurrence order.
+// Note: b is fully consumed into a set when iteration starts; a is streamed.
+func Difference[T comparable](a, b Stream[T]) Stream[T] {
+	return filterBySet(a, b, false)
+}
+
+// Union lazily yields the distinct elements of a followed by those of b not
+// already seen, in first-occurrence order.
+func Union[T comparable](a, b Stream[T]) Stream[T] {
+	seqA, seqB := a.seq, b.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		seen := make(map[T]struct{})
+		for _, seq := range []iter.Seq[T]{seqA, seqB} {
+			for v := range seq {
+				if _, ok := seen[v]; ok {
+					continue
+				}
+				seen[v] = struct{}{}
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}}
+}
+
+// filterBySet yields the distinct elements of a whose membership in b
+// equals keep.
+func filterBySet[T comparable](a, b Stream[T], keep bool) Stream[T] {
+	seqA := a.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		inB := ToSet(b)
+		seen := make(map[T]struct{})
+		for v := range seqA {
+			if _, ok := seen[v]; ok {
+				continue
+			}
+			seen[v] = struct{}{}
+			if _, ok := inB[v]; ok != keep {
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// DiffBy is like Diff but compares elements by the key extracted from each.
+func DiffBy[T any, K comparable](a, b Stream[T], key func(T) K) (onlyA, onlyB []T) {
+	var firstA []T
+	inA := make(map[K]bool) // key → also seen in b
+	for v := range a.seq {
+		k := key(v)
+		if _, ok := inA[k]; !ok {
+			inA[k] = false
+			firstA = append(firstA, v)
+		}
+	}
+
+	onlyB = []T{}
+	seenB := make(map[K]struct{})
+	for v := range b.seq {
+		k := key(v)
+		if _, ok := inA[k]; ok {
+			inA[k] = true
+			continue
+		}
+		if _, ok := seenB[k]; !ok {
+			seenB[k] = struct{}{}
+			onlyB = append(onlyB, v)
+		}
+	}
+
+	onlyA = []T{}
+	for _, v := range firstA {
+		if !inA[key(v)] {
+			onlyA = append(onlyA, v)
+		}
+	}
+	return onlyA, onlyB
+}
+
+// ToOrderedPairs builds key/value pairs from the Stream in first-seen key
+// order. When a key repeats, its value is replaced (last wins) but it keeps
+// its original position. Use this instead of Associate when the output order
+// must be deterministic, e.g. for rendering reports.
+//
+//	rows := stream.ToOrderedPairs(orders, func(o Order) (string, float64) {
+//	    return o.Product, o.Amount
+//	})
+func ToOrderedPairs[T any, K comparable, V any](s Stream[T], kv func(T) (K, V)) []Pair[K, V] {
+	result := []Pair[K, V]{}
+	index := make(map[K]int)
+	for v := range s.seq {
+		k, val := kv(v)
+		if i, ok := index[k]; ok {
+			result[i].Second = val
+			continue
+		}
+		index[k] = len(result)
+		result = append(result, Pair[K, V]{First: k, Second: val})
+	}
+	return result
+}
+
+// EqualUnordered reports whether two Streams contain the same elements with
+// the same multiplicities, in any order. Useful for asserting on the output
+// of unordered operations.
+//
+//	stream.EqualUnordered(stream.Of(1, 2, 2), stream.Of(2, 1, 2)) // true
+func EqualUnordered[T comparable](a, b Stream[T]) bool {
+	return EqualUnorderedBy(a, b, func(v T) T { return v })
+}
+
+// EqualUnorderedBy is like EqualUnordered but compares elements by the key
+// extracted from each.
+func EqualUnorderedBy[T any, K comparable](a, b Stream[T], key func(T) K) bool {
+	counts := make(map[K]int)
+	for v := range a.seq {
+		counts[key(v)]++
+	}
+	for v := range b.seq {
+		k := key(v)
+		if counts[k] == 0 {
+			return false
+		}
+		counts[k]--
+	}
+	for _, n := range counts {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// GroupAdjacent lazily groups consecutive elements sharing the same key,
+// yielding each (key, group) pair as soon as its run ends. Only the current
+// run is buffered, so it works on large or infinite key-sorted input.
+// Note: Elements are grouped by adjacency, not globally; sort by key first
+// (or use GroupBy) if equal keys may be scattered.
+//
+//	stream.GroupAdjacent(stream.Of("a1", "a2", "b1", "a3"), func(s string) byte { return s[0] })
+//	// yields {'a', [a1 a2]}, {'b', [b1]}, {'a', [a3]}
+func GroupAdjacent[T any, K comparable](s Stream[T], key func(T) K) Stream[Pair[K, []T]] {
+	seq := s.seq
+	return Stream[Pair[K, []T]]{seq: func(yield func(Pair[K, []T]) bool) {
+		var current K
+		var group []T
+		for v := range seq {
+			k := key(v)
+			if len(group) > 0 && k != current {
+				if !yield(Pair[K, []T]{First: current, Second: group}) {
+					return
+				}
+				group = nil
+			}
+			current = k
+			group = append(group, v)
+		}
+		if len(group) > 0 {
+			yield(Pair[K, []T]{First: current, Second: group})
+		}
+	}}
+}
+
+// Compact lazily drops elements equal to the immediately preceding one,
+// collapsing adjacent runs like Unix uniq. Unlike Distinct, equal elements
+// that are not adjacent are kept.
+//
+//	stream.Compact(stream.Of(1, 1, 2, 1)) // 1, 2, 1
+func Compact[T comparable](s Stream[T]) Stream[T] {
+	return CompactBy(s, func(v T) T { return v })
+}
+
+// CompactBy is like Compact but compares elements by the key extracted
+// from each, keeping the first element of every run.
+func CompactBy[T any, K comparable](s Stream[T], key func(T) K) Stream[T] {
+	seq := s.seq
+	return Stream[T]{seq: func(yield func(T) bool) {
+		var last K
+		started := false
+		for v := range seq {
+			k := key(v)
+			if started && k == last {
+				continue
+			}
+			last, started = k, true
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// RunLengthEncode lazily yields each run of equal adjacent elements as a
+// (value, length) Pair, emitted when the run ends. Only the current value
+// and its count are kept, so it works on arbitrarily long runs.
+//
+//	stream.RunLengthEncode(stream.Of("A", "A", "B", "A", "A", "A"))
+//	// yields {A, 2}, {B, 1}, {A, 3}
+func RunLengthEncode[T comparable](s Stream[T]) Stream[Pair[T, int]] {
+	seq := s.seq
+	return Stream[Pair[T, int]]{seq: func(yield func(Pair[T, int]) bool) {
+		var current T
+		count := 0
+		for v := range seq {
+			if count > 0 && v != current {
+				if !yield(Pair[T, int]{First: current, Second: count}) {
+					return
+				}
+				count = 0
+			}
+			current = v
+			count++
+		}
+		if count > 0 {
+			yield(Pair[T, int]{First: current, Second: count})
+		}
+	}}
+}
+
+// ChunkBy collects consecutive elements sharing the same key into groups,
+// starting a new group whenever the key changes. For a lazy variant that
+// buffers only the current run, use GroupAdjacent.
+//
+//	stream.ChunkBy(stream.Of("A", "A", "B", "A"), func(s string) string { return s })
+//	// [[A A] [B] [A]]
+func ChunkBy[T any, K comparable](s Stream[T], key func(T) K) [][]T {
+	groups := [][]T{}
+	for p := range GroupAdjacent(s, key).seq {
+		groups = append(groups, p.Second)
+	}
+	return groups
+}
+
+// TumblingWindow lazily folds each non-overlapping window of size elements
+// into a U, yielding one result per full window. A trailing partial window
+// is dropped; use TumblingWindowPartial to include it. Returns an empty
+// Stream for size <= 0.
+// Note: The window slice is reused between calls; fold must not retain it.
+//
+//	sums := stream.TumblingWindow(readings, 60, func(w []float64) float64 {
+//	    return stream.Sum(stream.Of(w...))
+//	})
+func TumblingWindow[T, U any](s Stream[T], size int, fold func([]T) U) Stream[U] {
+	return tumblingWindow(s, size, fold, false)
+}
+
+// TumblingWindowPartial is like TumblingWindow but also folds the final
+// partial window when the Stream length is not a multiple of size.
+func TumblingWindowPartial[T, U any](s Stream[T], size int, fold func([]T) U) Stream[U] {
+	return tumblingWindow(s, size, fold, true)
+}
+
+func tumblingWindow[T, U any](s Stream[T], size int, fold func([]T) U, partial bool) Stream[U] {
+	seq := s.seq
+	return Stream[U]{seq: func(yield func(U) bool) {
+		if size <= 0 {
+			return
+		}
+		window := make([]T, 0, size)
+		for v := range seq {
+			window = append(window, v)
+			if len(window) < size {
+				continue
+			}
+			if !yield(fold(window)) {
+				return
+			}
+			window = window[:0]
+		}
+		if partial && len(window) > 0 {
+			yield(fold(window))
+		}
+	}}
+}
+
+// ZipApply lazily pairs each element with gen(index), attaching generated
+// metadata without needing a second Stream.
+//
+//	stream.ZipApply(stream.Of("a", "b", "c"), func(i int) int { return i * i })
+//	// yields {"a", 0}, {"b", 1}, {"c", 4}
+func ZipApply[T, U any](s Stream[T], gen func(int) U) Stream[Pair[T, U]] {
+	return MapIndexed(s, func(i int, v T) Pair[T, U] {
+		return Pair[T, U]{First: v, Second: gen(i)}
+	})
+}
+
+// Coalesce lazily zips the Streams positionally and yields, at each position,
+// the first non-zero value among them (like SQL COALESCE per row). If every
+// value at a position is zero, the zero value is yielded. Stops when the
+// shortest Stream is exhausted.
+//
+//	merged := stream.Coalesce(primary, fallback, defaults)
+func Coalesce[T comparable](streams ...Stream[T]) Stream[T] {
+	var zero T
+	return CoalesceFunc(func(v T) bool { return v != zero }, streams...)
+}
+
+// CoalesceFunc is like Coalesce but uses isPresent to decide whether a value
+// fills its position.
+func CoalesceFunc[T any](isPresent func(T) bool, streams ...Stream[T]) Stream[T] {
+	return Stream[T]{seq: func(yield func(T) bool) {
+		if len(streams) == 0 {
+			return
+		}
+		nexts := make([]func() (T, bool), len(streams))
+		for i, s := range streams {
+			next, stop := iter.Pull(s.seq)
+			defer stop()
+			nexts[i] = next
+		}
+		for {
+			var result T
+			found := false
+			for _, next := range nexts {
+				v, ok := next()
+				if !ok {
+					return
+				}
+				if !found && isPresent(v) {
+					result, found = v, true
+				}
+			}
+			if !yield(result) {
+				return
+			}
+		}
+	}}
+}
+
+// MapWindow lazily applies fn to the trailing window of up to size elements
+// ending at each element, yielding one result per input element. The window
+// grows from a single element until it holds size elements and then slides
+// forward, oldest first. The window slice is a view into an internal ring
+// buffer, so fn must not retain or modify it. Returns an empty Stream for a
+// non-positive size.
+//
+//	spikes := stream.MapWindow(readings, 10, func(w []float64) bool {
+//	    return w[len(w)-1] > 2*stream.Avg(stream.Of(w...))
+//	})
+func MapWindow[T, U any](s Stream[T], size int, fn func(window []T) U) Stream[U] {
+	if size <= 0 {
+		return Stream[U]{seq: func(yield func(U) bool) {}}
+	}
+	seq := s.seq
+	return Stream[U]{seq: func(yield func(U) bool) {
+		// Each element is stored twice, size apart, so the latest n elements
+		// are always contiguous in buf.
+		buf := make([]T, 2*size)
+		pos, n := 0, 0
+		for v := range seq {
+			buf[pos], buf[pos+size] = v, v
+			n = min(n+1, size)
+			end := pos + size + 1
+			if !yield(fn(buf[end-n : end])) {
+				return
+			}
+			pos = (pos + 1) % size
+		}
+	}}
+}
+
+// MergeJoin performs a streaming inner join of two Streams that are both
+// sorted by key in ascending order, emitting a Pair for every left/right
+// combination with equal keys. It runs in O(n+m) and buffers only the
+// right-side run for the current key, so neither input is held in memory.
+// If either input is not sorted by key, the result is unspecified.
+//
+//	stream.MergeJoin(orders, customers,
+//		func(o Order) int { return o.CustomerID },
+//		func(c Customer) int { return c.ID })
+func MergeJoin[L, R any, K cmp.Ordered](left Stream[L], right Stream[R], lk func(L) K, rk func(R) K) Stream[Pair[L, R]] {
+	lseq, rseq := left.seq, right.seq
+	return Stream[Pair[L, R]]{seq: func(yield func(Pair[L, R]) bool) {
+		next, stop := iter.Pull(rseq)
+		defer stop()
+		r, ok := next()
+		var run []R
+		var runKey K
+		hasRun := false
+		for l := range lseq {
+			k := lk(l)
+			if !hasRun || k != runKey {
+				for ok && rk(r) < k {
+					r, ok = next()
+				}
+				run, runKey, hasRun = run[:0], k, true
+				for ok && rk(r) == k {
+					run = append(run, r)
+					r, ok = next()
+				}
+			}
+			if len(run) == 0 && !ok {
+				return
+			}
+			for _, rv := range run {
+				if !yield(Pair[L, R]{First: l, Second: rv}) {
+					return
+				}
+			}
+		}
+	}}
+}